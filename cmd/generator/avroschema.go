@@ -0,0 +1,232 @@
+//go:build generator
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// avroSchema is a parsed Avro schema, which is used to compare the schemes of different versions.
+type avroSchema struct {
+	// items is the schema of the items of an array or the values of a map.
+	items *avroSchema
+	// kind is the name of a primitive type, or array, map, union, enum or record.
+	kind string
+	// name is the full name of an enum or a record.
+	name        string
+	logicalType string
+	symbols     []string
+	branches    []*avroSchema
+	fields      []avroSchemaField
+}
+
+// avroSchemaField is a field of a record.
+type avroSchemaField struct {
+	schema     *avroSchema
+	name       string
+	hasDefault bool
+}
+
+// avroPrimitiveTypes are the primitive types of Avro.
+var avroPrimitiveTypes = map[string]struct{}{
+	"null":    {},
+	"boolean": {},
+	"int":     {},
+	"long":    {},
+	"float":   {},
+	"double":  {},
+	"bytes":   {},
+	"string":  {},
+}
+
+// String returns the type in a short form for the messages, like union {null, array<long>}.
+func (s *avroSchema) String() string {
+	var t string
+
+	switch s.kind {
+	case "array", "map":
+		t = fmt.Sprintf("%s<%s>", s.kind, s.items)
+	case "union":
+		branches := make([]string, 0, len(s.branches))
+		for _, b := range s.branches {
+			branches = append(branches, b.String())
+		}
+
+		t = fmt.Sprintf("union {%s}", strings.Join(branches, ", "))
+	case "enum", "record":
+		t = fmt.Sprintf("%s %s", s.kind, s.name)
+	default:
+		t = s.kind
+	}
+
+	if s.logicalType != "" {
+		t = fmt.Sprintf("%s(%s)", t, s.logicalType)
+	}
+
+	return t
+}
+
+// fullAvroName returns the full name of the name in the namespace.
+func fullAvroName(name, namespace string) string {
+	if strings.Contains(name, ".") || namespace == "" {
+		return name
+	}
+
+	return namespace + "." + name
+}
+
+// shortAvroName returns the name without its namespace.
+func shortAvroName(fullName string) string {
+	return fullName[strings.LastIndex(fullName, ".")+1:]
+}
+
+// parseAvsc parses the Avro JSON schema of a record.
+func parseAvsc(content []byte) (*avroSchema, error) {
+	var raw any
+
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode Avro JSON schema: %w", err)
+	}
+
+	p := avscParser{named: make(map[string]*avroSchema)}
+
+	schema, err := p.parse(raw, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if schema.kind != "record" {
+		return nil, fmt.Errorf("expected a record, got %s", schema)
+	}
+
+	return schema, nil
+}
+
+// avscParser parses Avro JSON schemas, tracking the named types, which are referenced by their names after their
+// definition.
+type avscParser struct {
+	named map[string]*avroSchema
+}
+
+func (p avscParser) parse(raw any, namespace string) (*avroSchema, error) {
+	switch t := raw.(type) {
+	case string:
+		if _, primitive := avroPrimitiveTypes[t]; primitive {
+			return &avroSchema{kind: t}, nil
+		}
+
+		if named, exists := p.named[fullAvroName(t, namespace)]; exists {
+			return named, nil
+		}
+
+		return nil, fmt.Errorf("unknown type %s", t)
+	case []any:
+		union := &avroSchema{kind: "union"}
+
+		for _, branch := range t {
+			s, err := p.parse(branch, namespace)
+			if err != nil {
+				return nil, err
+			}
+
+			union.branches = append(union.branches, s)
+		}
+
+		return union, nil
+	case map[string]any:
+		return p.parseComplex(t, namespace)
+	default:
+		return nil, fmt.Errorf("unexpected schema %v", raw)
+	}
+}
+
+func (p avscParser) parseComplex(raw map[string]any, namespace string) (*avroSchema, error) {
+	kind, _ := raw["type"].(string)
+	logicalType, _ := raw["logicalType"].(string)
+
+	switch kind {
+	case "array", "map":
+		itemsKey := "items"
+		if kind == "map" {
+			itemsKey = "values"
+		}
+
+		items, err := p.parse(raw[itemsKey], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", kind, err)
+		}
+
+		return &avroSchema{kind: kind, items: items, logicalType: logicalType}, nil
+	case "enum", "record":
+		return p.parseNamed(raw, kind, namespace)
+	}
+
+	if _, primitive := avroPrimitiveTypes[kind]; primitive {
+		return &avroSchema{kind: kind, logicalType: logicalType}, nil
+	}
+
+	// a type can be a schema itself, like {"type": ["null", "string"]}
+	if t, exists := raw["type"]; exists && kind == "" {
+		return p.parse(t, namespace)
+	}
+
+	return nil, fmt.Errorf("unsupported type %v", raw["type"])
+}
+
+func (p avscParser) parseNamed(raw map[string]any, kind, namespace string) (*avroSchema, error) {
+	name, _ := raw["name"].(string)
+	if name == "" {
+		return nil, fmt.Errorf("%s without name", kind)
+	}
+
+	if ns, ok := raw["namespace"].(string); ok {
+		namespace = ns
+	}
+
+	schema := &avroSchema{kind: kind, name: fullAvroName(name, namespace)}
+	p.named[schema.name] = schema
+
+	// the names in a named type are relative to its namespace
+	namespace = schema.name[:max(strings.LastIndex(schema.name, "."), 0)]
+
+	if kind == "enum" {
+		symbols, _ := raw["symbols"].([]any)
+		for _, symbol := range symbols {
+			s, ok := symbol.(string)
+			if !ok {
+				return nil, fmt.Errorf("enum %s: invalid symbol %v", name, symbol)
+			}
+
+			schema.symbols = append(schema.symbols, s)
+		}
+
+		return schema, nil
+	}
+
+	fields, _ := raw["fields"].([]any)
+	for _, rawField := range fields {
+		f, ok := rawField.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("record %s: invalid field %v", name, rawField)
+		}
+
+		fieldName, _ := f["name"].(string)
+
+		fieldSchema, err := p.parse(f["type"], namespace)
+		if err != nil {
+			return nil, fmt.Errorf("record %s: field %s: %w", name, fieldName, err)
+		}
+
+		_, hasDefault := f["default"]
+
+		schema.fields = append(schema.fields, avroSchemaField{
+			name:       fieldName,
+			schema:     fieldSchema,
+			hasDefault: hasDefault,
+		})
+	}
+
+	return schema, nil
+}
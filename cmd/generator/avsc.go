@@ -0,0 +1,217 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// avscRecord is an Avro record schema in JSON, where the annotations of the record, like the df_datatype property
+// of the data fabric, are its properties.
+type avscRecord struct {
+	Type       string
+	Name       string
+	Namespace  string
+	Doc        string
+	Properties []schemeAnnotation
+	Fields     []avscField
+}
+
+// avscMember is a member of a JSON object.
+type avscMember struct {
+	value any
+	name  string
+}
+
+// MarshalJSON marshals the record with its properties between its doc and its fields, in their order.
+// The namespace is omitted when it is empty.
+func (r avscRecord) MarshalJSON() ([]byte, error) {
+	members := []avscMember{
+		{name: "type", value: r.Type},
+		{name: "name", value: r.Name},
+	}
+
+	if r.Namespace != "" {
+		members = append(members, avscMember{name: "namespace", value: r.Namespace})
+	}
+
+	members = append(members, avscMember{name: "doc", value: r.Doc})
+
+	for _, p := range r.Properties {
+		members = append(members, avscMember{name: p.Name, value: p.Value})
+	}
+
+	members = append(members, avscMember{name: "fields", value: r.Fields})
+
+	buf := bytes.NewBufferString("{")
+
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+
+	// the encoder ends the values with new lines, which are insignificant whitespace
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := encoder.Encode(m.name); err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte(':')
+
+		if err := encoder.Encode(m.value); err != nil {
+			return nil, err
+		}
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// avscField is a field of an Avro record schema in JSON.
+type avscField struct {
+	Name string `json:"name"`
+	Type any    `json:"type"`
+	Doc  string `json:"doc,omitempty"`
+	// Default is the JSON of the default value. A nil Default means the field has no default value.
+	Default json.RawMessage `json:"default,omitempty"`
+}
+
+// avscEnum is an Avro enum schema in JSON.
+type avscEnum struct {
+	Type    string   `json:"type"`
+	Name    string   `json:"name"`
+	Symbols []string `json:"symbols"`
+}
+
+// avscArray is an Avro array schema in JSON.
+type avscArray struct { //nolint:govet // the fields are marshaled in the order of the Avro specification
+	Type  string `json:"type"`
+	Items any    `json:"items"`
+}
+
+// avscMap is an Avro map schema in JSON.
+type avscMap struct { //nolint:govet // the fields are marshaled in the order of the Avro specification
+	Type   string `json:"type"`
+	Values any    `json:"values"`
+}
+
+// avscLogicalType is an Avro primitive schema annotated with a logical type in JSON.
+type avscLogicalType struct {
+	Type        string `json:"type"`
+	LogicalType string `json:"logicalType"`
+}
+
+// avscTimestampTypes are the Avro timestamp logical types for the time precisions, like avroTimestampTypes.
+var avscTimestampTypes = map[timePrecision]any{
+	timePrecisionSeconds:      "long",
+	timePrecisionMilliseconds: avscLogicalType{Type: "long", LogicalType: "timestamp-millis"},
+	timePrecisionMicroseconds: avscLogicalType{Type: "long", LogicalType: "timestamp-micros"},
+	timePrecisionNanoseconds:  avscLogicalType{Type: "long", LogicalType: "timestamp-nanos"},
+}
+
+// avscNullDefault is the JSON of the null default value of the nullable fields.
+var avscNullDefault = json.RawMessage("null")
+
+// getAvscType returns the Avro type of the field value, like getAvroType. Unlike in Avro IDL, an enum is defined
+// where it is first used and referenced by its name afterwards, so definedEnums tracks the defined enums.
+func getAvscType(f field, enums map[string]schemeEnum, definedEnums map[string]struct{}) any {
+	if isAvroEnum(f) {
+		if _, defined := definedEnums[f.named.name]; defined {
+			return f.named.name
+		}
+
+		definedEnums[f.named.name] = struct{}{}
+
+		return avscEnum{
+			Type:    "enum",
+			Name:    f.named.name,
+			Symbols: enums[f.named.name].Symbols,
+		}
+	}
+
+	if f.timeKind == timeKindTimestamp {
+		return avscTimestampTypes[f.timePrecision]
+	}
+
+	return getAvroPrimitiveType(f.fieldType)
+}
+
+// generateAvsc generates the Avro JSON schema (.avsc) of the record, which is equivalent to the record of the Avro IDL
+// scheme generated by generateScheme, for the tools that can't compile Avro IDL.
+func generateAvsc(writer io.Writer, cfg schemeGenConfig) error {
+	schemeEnums, err := getAvroEnums(cfg.fields)
+	if err != nil {
+		return err
+	}
+
+	enums := make(map[string]schemeEnum, len(schemeEnums))
+	for _, e := range schemeEnums {
+		enums[e.Name] = e
+	}
+
+	definedEnums := make(map[string]struct{}, len(schemeEnums))
+
+	var createAvscFields func([]field) []avscField
+	createAvscFields = func(fields []field) []avscField {
+		avscFields := make([]avscField, 0, len(fields))
+
+		for _, f := range fields {
+			var valueType any
+
+			switch {
+			case f.embeddedStruct:
+				avscFields = append(avscFields, createAvscFields(f.embeddedStructFields)...)
+				continue
+			case f.slice:
+				valueType = avscArray{Type: "array", Items: getAvscType(f, enums, definedEnums)}
+			case f.stringMap:
+				valueType = avscMap{Type: "map", Values: getAvscType(f, enums, definedEnums)}
+			default:
+				valueType = getAvscType(f, enums, definedEnums)
+			}
+
+			avscFields = append(avscFields, avscField{
+				Name:    getAvroName(f.attributeKey()),
+				Type:    []any{"null", valueType},
+				Doc:     f.docString,
+				Default: avscNullDefault,
+			})
+		}
+
+		return avscFields
+	}
+
+	envelopeFields := cfg.envelope.getFields()
+
+	avscFields := make([]avscField, 0, len(envelopeFields))
+	for _, f := range envelopeFields {
+		avscFields = append(avscFields, avscField{Name: f.Name, Type: f.Type, Doc: f.Doc})
+	}
+
+	avscFields = append(avscFields, createAvscFields(cfg.fields)...)
+
+	record := avscRecord{
+		Type:       "record",
+		Name:       cfg.record,
+		Namespace:  cfg.namespace,
+		Doc:        cfg.record + " is the telemetry data for the product.",
+		Properties: cfg.envelope.getAnnotations(cfg.dataFabricDataType),
+		Fields:     avscFields,
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(record); err != nil {
+		return fmt.Errorf("failed to encode Avro JSON schema: %w", err)
+	}
+
+	return nil
+}
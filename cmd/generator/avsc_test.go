@@ -0,0 +1,133 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginx/telemetry-exporter/cmd/generator/tests"
+)
+
+func TestGenerateAvscOfData(t *testing.T) {
+	t.Parallel()
+
+	_ = tests.Data{} // depends on the type being defined
+
+	// timeTypes are the Avro types of the time.Time fields by the time precisions
+	timeTypes := map[timePrecision]string{
+		timePrecisionSeconds:      "union {null, long}",
+		timePrecisionMilliseconds: "union {null, long(timestamp-millis)}",
+		timePrecisionMicroseconds: "union {null, long(timestamp-micros)}",
+		timePrecisionNanoseconds:  "union {null, long(timestamp-nanos)}",
+	}
+
+	for precision, timeType := range timeTypes {
+		t.Run(string(precision), func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			parseCfg := parsingConfig{
+				pkgName:       "tests",
+				typeNames:     []string{"Data"},
+				loadPattern:   "github.com/nginx/telemetry-exporter/cmd/generator/tests",
+				buildFlags:    []string{"-tags=generator"},
+				timePrecision: precision,
+			}
+
+			pResult, err := parse(parseCfg)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			schemeCfg := schemeGenConfig{
+				namespace:          "gateway.nginx.org",
+				protocol:           "NGFProductTelemetry",
+				dataFabricDataType: "ngf-product-telemetry",
+				record:             pResult.types[0].name,
+				fields:             pResult.types[0].fields,
+			}
+
+			var avscBuf bytes.Buffer
+
+			g.Expect(generateAvsc(&avscBuf, schemeCfg)).To(Succeed())
+
+			avscRecord, err := parseAvsc(avscBuf.Bytes())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// the record has the envelope fields and then the fields of the struct, like the Avro IDL scheme
+			var expectedNames []string
+			for _, f := range schemeCfg.envelope.getFields() {
+				expectedNames = append(expectedNames, f.Name)
+			}
+
+			var addNames func([]field)
+			addNames = func(fields []field) {
+				for _, f := range fields {
+					if f.embeddedStruct {
+						addNames(f.embeddedStructFields)
+						continue
+					}
+
+					expectedNames = append(expectedNames, getAvroName(f.attributeKey()))
+				}
+			}
+
+			addNames(schemeCfg.fields)
+
+			fieldNames := make([]string, 0, len(avscRecord.fields))
+			fieldTypes := make(map[string]string, len(avscRecord.fields))
+
+			for _, f := range avscRecord.fields {
+				fieldNames = append(fieldNames, f.name)
+				fieldTypes[f.name] = f.schema.String()
+			}
+
+			g.Expect(fieldNames).To(Equal(expectedNames))
+			g.Expect(fieldTypes).To(HaveKeyWithValue("eventTime", "long"))
+			g.Expect(fieldTypes).To(HaveKeyWithValue("SomePlatforms", "union {null, array<enum gateway.nginx.org.Platform>}"))
+			g.Expect(fieldTypes).To(HaveKeyWithValue("SomeTime", timeType))
+		})
+	}
+}
+
+func TestGenerateAvsc(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "gateway.nginx.org",
+		protocol:           "NGFProductTelemetry",
+		dataFabricDataType: "ngf-product-telemetry",
+		record:             "Data",
+		fields: []field{
+			{
+				docString:     "SomeTime is a time.",
+				name:          "SomeTime",
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionMicroseconds,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateAvsc(&buf, schemeCfg)).To(Succeed())
+
+	var record map[string]any
+
+	g.Expect(json.Unmarshal(buf.Bytes(), &record)).To(Succeed())
+
+	g.Expect(record).To(HaveKeyWithValue("df_datatype", "ngf-product-telemetry"))
+	g.Expect(record).To(HaveKeyWithValue("namespace", "gateway.nginx.org"))
+	g.Expect(record["fields"]).To(HaveLen(4))
+	g.Expect(record["fields"]).To(ContainElement(map[string]any{
+		"name":    "SomeTime",
+		"type":    []any{"null", map[string]any{"type": "long", "logicalType": "timestamp-micros"}},
+		"doc":     "SomeTime is a time.",
+		"default": nil,
+	}))
+}
@@ -3,12 +3,17 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"go/types"
 	"io"
+	"maps"
 	"reflect"
+	"slices"
+	"strconv"
 	"strings"
-	"text/template"
+
+	"golang.org/x/tools/imports"
 
 	"github.com/nginx/telemetry-exporter/pkg/telemetry"
 )
@@ -16,6 +21,7 @@ import (
 var telemetryPackagePath = reflect.TypeOf((*telemetry.Exportable)(nil)).Elem().PkgPath()
 
 const codeTemplate = `{{- if .BuildTags }}//go:build {{ .BuildTags }}
+
 {{ end -}}
 package {{ .PackageName }}
 
@@ -24,22 +30,48 @@ This is a generated file. DO NOT EDIT.
 */
 
 import (
+	{{- range .StandardImports }}
+	"{{ . }}"
+	{{- end }}
+	{{- if .StandardImports }}
+{{ end }}
 	"go.opentelemetry.io/otel/attribute"
+	{{- if or .Imports .TelemetryPackagePath }}
+{{ end }}
+	{{- range .Imports }}
+	"{{ . }}"
+	{{- end }}
 	{{- if .TelemetryPackagePath }}
-
 	{{ if .TelemetryPackageAlias }}{{ .TelemetryPackageAlias }} {{ end }}"{{ .TelemetryPackagePath }}"
 	{{- end }}
 )
+{{- range .Types }}
 
 func (d *{{ .StructName }}) Attributes() []attribute.KeyValue {
 	var attrs []attribute.KeyValue
 
-	{{- if .SchemeDataType }}
-	attrs = append(attrs, attribute.String("dataType", "{{ .SchemeDataType }}"))
+	{{- if and .SchemeDataType .DataTypeKey }}
+	attrs = append(attrs, attribute.String("{{ .DataTypeKey }}", "{{ .SchemeDataType }}"))
 	{{- end }}
 
 	{{- range .Fields }}
-	{{- if .AttributesSource }}
+	{{- if .MapField }}
+	for _, k := range slices.Sorted(maps.Keys(d.{{ .MapField }})) {
+		attrs = append(attrs, {{ .AttributesSource }})
+	}
+	{{- else if .SliceField }}
+	{{ if .Condition }}if {{ .Condition }} {{ end }}{
+		values := make([]{{ .SliceValueType }}, 0, len(d.{{ .SliceField }}))
+		for _, v := range d.{{ .SliceField }} {
+			values = append(values, {{ .SliceValueSource }})
+		}
+		attrs = append(attrs, {{ .AttributesSource }})
+	}
+	{{- else if .Condition }}
+	if {{ .Condition }} {
+		attrs = append(attrs, {{ .AttributesSource }})
+	}
+	{{- else if .AttributesSource }}
 	attrs = append(attrs, {{ .AttributesSource }})
 	{{- end }}
 	{{- end }}
@@ -47,7 +79,151 @@ func (d *{{ .StructName }}) Attributes() []attribute.KeyValue {
 	return attrs
 }
 
-var _ {{ .ExportablePackagePrefix }}Exportable = (*{{ .StructName }})(nil)
+// {{ .StructName }}FromAttributes creates {{ .StructName }} from the attributes returned by its Attributes method.
+func {{ .StructName }}FromAttributes(attrs []attribute.KeyValue) ({{ .StructName }}, error) {
+	var d {{ .StructName }}
+
+	{{- $structName := .StructName }}
+	{{- range .FromAttributesFields }}
+	{{- if .Unsupported }}
+
+	// {{ .Name }} is not set, because {{ .Unsupported }}.
+	{{- else if .EmbeddedFunc }}
+
+	{
+		v, err := {{ .EmbeddedFunc }}(attrs)
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		d.{{ .Name }} = v
+	}
+	{{- else if .MapValueType }}
+
+	{
+		values, err := {{ $.ExportablePackagePrefix }}LookupAttributeMap(attrs, "{{ .Key }}", attribute.{{ .ValueType }})
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		if values != nil {
+			d.{{ .Name }} = make(map[string]{{ .MapValueType }}, len(values))
+			for k, v := range values {
+				{{- if .Checked }}
+				value, err := {{ .ValueSource }}
+				if err != nil {
+					return {{ $structName }}{}, err
+				}
+				d.{{ .Name }}[k] = value
+				{{- else }}
+				d.{{ .Name }}[k] = {{ .ValueSource }}
+				{{- end }}
+			}
+		}
+	}
+	{{- else }}
+
+	{
+		{{- if .Required }}
+		v, err := {{ $.ExportablePackagePrefix }}RequireAttribute(attrs, "{{ .Key }}", attribute.{{ .ValueType }})
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		{{- else }}
+		v, exists, err := {{ $.ExportablePackagePrefix }}LookupAttribute(attrs, "{{ .Key }}", attribute.{{ .ValueType }})
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		if exists {
+		{{- end }}
+		{{- if .SliceValueType }}
+		values := {{ .SliceSource }}
+		d.{{ .Name }} = make([]{{ .SliceValueType }}, 0, len(values))
+		for _, e := range values {
+			{{- if .Checked }}
+			value, err := {{ .ValueSource }}
+			if err != nil {
+				return {{ $structName }}{}, err
+			}
+			d.{{ .Name }} = append(d.{{ .Name }}, value)
+			{{- else }}
+			d.{{ .Name }} = append(d.{{ .Name }}, {{ .ValueSource }})
+			{{- end }}
+		}
+		{{- else if and .Pointer .Checked }}
+		value, err := {{ .ValueSource }}
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		d.{{ .Name }} = &value
+		{{- else if .Pointer }}
+		value := {{ .ValueSource }}
+		d.{{ .Name }} = &value
+		{{- else if .Checked }}
+		d.{{ .Name }}, err = {{ .ValueSource }}
+		if err != nil {
+			return {{ $structName }}{}, err
+		}
+		{{- else }}
+		d.{{ .Name }} = {{ .ValueSource }}
+		{{- end }}
+		{{- if not .Required }}
+		}
+		{{- end }}
+	}
+	{{- end }}
+	{{- end }}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of {{ .StructName }},
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *{{ .StructName }}) TelemetryFields() []{{ $.ExportablePackagePrefix }}FieldInfo {
+	return []{{ $.ExportablePackagePrefix }}FieldInfo{
+		{{- range .TelemetryFields }}
+		{
+			Key:         {{ printf "%q" .Key }},
+			GoType:      {{ printf "%q" .GoType }},
+			AvroType:    {{ printf "%q" .AvroType }},
+			Description: {{ printf "%q" .Description }},
+			Source:      {{ printf "%q" .Source }},
+		},
+		{{- end }}
+	}
+}
+
+var _ {{ $.ExportablePackagePrefix }}Describable = (*{{ .StructName }})(nil)
+{{- if .ValidationRules }}
+
+// Validate returns an error if the fields of {{ .StructName }} violate their validation rules.
+func (d *{{ .StructName }}) Validate() error {
+	var errs []error
+
+	{{- range .ValidationRules }}
+	{{- if .EmbeddedField }}
+
+	if err := d.{{ .EmbeddedField }}.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	{{- else }}
+
+	if {{ .Condition }} {
+		errs = append(errs, &{{ $.ExportablePackagePrefix }}ValidationError{
+			Field:   "{{ .Field }}",
+			Message: "{{ .Message }}",
+		})
+	}
+	{{- end }}
+	{{- end }}
+
+	return errors.Join(errs...)
+}
+
+var _ {{ $.ExportablePackagePrefix }}Validatable = (*{{ .StructName }})(nil)
+{{- end }}
+
+var _ {{ $.ExportablePackagePrefix }}Exportable = (*{{ .StructName }})(nil)
+{{- end }}
 `
 
 type codeGen struct {
@@ -55,21 +231,137 @@ type codeGen struct {
 	TelemetryPackagePath    string
 	TelemetryPackageAlias   string
 	ExportablePackagePrefix string
-	StructName              string
-	SchemeDataType          string
 	BuildTags               string
-	Fields                  []codeField
+	// StandardImports are the paths of the imported packages of the standard library.
+	StandardImports []string
+	// Imports are the paths of the other imported packages, except for the telemetry package.
+	Imports []string
+	Types   []codeType
+}
+
+type codeType struct {
+	StructName     string
+	SchemeDataType string
+	// DataTypeKey is the key of the attribute of the data type: the data type field of the envelope of the schemes.
+	// The attribute is only added when both the key and the data type are set.
+	DataTypeKey          string
+	Fields               []codeField
+	FromAttributesFields []fromAttributesField
+	// ValidationRules are the validation rules of the fields. The Validate method is only generated when it is set.
+	ValidationRules []validationRule
+	TelemetryFields []telemetryField
+}
+
+// telemetryField is the metadata of a field returned by the TelemetryFields method. See telemetry.FieldInfo.
+type telemetryField struct {
+	Key         string
+	GoType      string
+	AvroType    string
+	Description string
+	Source      string
+}
+
+// getGoType returns the Go type of the field, where named types are qualified by the name of their package.
+// Named slice and map types are described by their underlying types.
+func getGoType(f field) string {
+	var valueType string
+
+	switch {
+	case f.timeKind == timeKindTimestamp:
+		valueType = "time.Time"
+	case f.timeKind == timeKindDuration:
+		valueType = "time.Duration"
+	case f.named != nil:
+		valueType = getPackageName(f.named.packagePath) + "." + f.named.name
+	default:
+		valueType = types.Typ[f.fieldType].Name()
+	}
+
+	switch {
+	case f.pointer:
+		return "*" + valueType
+	case f.slice:
+		return "[]" + valueType
+	case f.stringMap:
+		return "map[string]" + valueType
+	default:
+		return valueType
+	}
+}
+
+// getTelemetryFields returns the metadata of the fields, including the fields of the embedded structs, whose source
+// is the embedded struct.
+func getTelemetryFields(source string, fields []field) []telemetryField {
+	var result []telemetryField
+
+	for _, f := range fields {
+		if f.embeddedStruct {
+			result = append(result, getTelemetryFields(f.embeddedStructName(), f.embeddedStructFields)...)
+			continue
+		}
+
+		result = append(result, telemetryField{
+			Key:         f.attributeKey(),
+			GoType:      getGoType(f),
+			AvroType:    getAvroFieldType(f),
+			Description: f.docString,
+			Source:      source,
+		})
+	}
+
+	return result
 }
 
 type codeField struct {
 	AttributesSource string
+	// MapField is the name of the map field. If set, AttributesSource is added for each key k of the map,
+	// in the sorted order of the keys.
+	MapField string
+	// SliceField is the name of the slice field that needs its values converted. If set, each value v of the slice
+	// is converted using SliceValueSource into the slice values of SliceValueType, which AttributesSource uses.
+	SliceField       string
+	SliceValueType   string
+	SliceValueSource string
+	// Condition is the condition under which the attributes of the field are added. If empty, they're always added.
+	Condition string
+}
+
+// fromAttributesField sets a field from its attributes in the FromAttributes function.
+type fromAttributesField struct {
+	// Name is the name of the field.
+	Name string
+	// Key is the key of the attribute of the field, or the prefix of the attribute keys of a map field.
+	Key string
+	// ValueType is the attribute.Type of the attribute value, like INT64.
+	ValueType string
+	// ValueSource converts the attribute value v to the field value. For map fields, it converts each attribute
+	// value v of the map. For slice fields with SliceValueType, it converts each value e of SliceSource.
+	ValueSource string
+	// SliceSource is the source of the slice of the attribute value, which is set with SliceValueType.
+	SliceSource string
+	// SliceValueType is the type of the values of the slice field, which is set when the values of the attribute
+	// slice need to be converted.
+	SliceValueType string
+	// MapValueType is the type of the values of the map field, which is only set for map fields.
+	MapValueType string
+	// EmbeddedFunc is the FromAttributes function of the embedded struct, which is only set for embedded structs.
+	EmbeddedFunc string
+	// Unsupported is the reason why the field can't be set from its attribute. If set, the field is left unset.
+	Unsupported string
+	// Required is true when the attribute must exist, because Attributes always adds it.
+	Required bool
+	// Pointer is true when the field is a pointer to the value.
+	Pointer bool
+	// Checked is true when ValueSource returns the value and an error, because the value is checked to be in the range
+	// of the type of the field.
+	Checked bool
 }
 
 func getAttributeType(kind types.BasicKind) string {
 	switch kind {
-	case types.Int64:
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.Uint8, types.Uint16, types.Uint32:
 		return "Int64"
-	case types.Float64:
+	case types.Float32, types.Float64:
 		return "Float64"
 	case types.String:
 		return "String"
@@ -80,38 +372,361 @@ func getAttributeType(kind types.BasicKind) string {
 	}
 }
 
+// getAttributeValueType returns the Go type of the value expected by the attribute constructor for the kind.
+func getAttributeValueType(kind types.BasicKind) string {
+	return strings.ToLower(getAttributeType(kind))
+}
+
+// isWidened returns true if the value of the kind needs to be converted to the type expected by the attribute
+// constructor.
+func isWidened(kind types.BasicKind) bool {
+	switch kind {
+	case types.Int64, types.Float64, types.String, types.Bool:
+		return false
+	default:
+		return true
+	}
+}
+
+// needsConversion returns true if the value of the field needs to be converted to the type expected by the attribute
+// constructor.
+func needsConversion(f field) bool {
+	return f.named != nil || f.timeKind != timeKindNone || isWidened(f.fieldType)
+}
+
+// isConvertedByMethod returns true if the value of the field is converted by calling its method.
+func isConvertedByMethod(f field) bool {
+	return (f.named != nil && f.named.stringer) || f.timeKind != timeKindNone
+}
+
+// timestampMethods are the time.Time methods that return the Unix time in the precision.
+var timestampMethods = map[timePrecision]string{
+	timePrecisionSeconds:      "Unix()",
+	timePrecisionMilliseconds: "UnixMilli()",
+	timePrecisionMicroseconds: "UnixMicro()",
+	timePrecisionNanoseconds:  "UnixNano()",
+}
+
+// durationMethods are the time.Duration methods that return the duration in the precision.
+var durationMethods = map[timePrecision]string{
+	timePrecisionSeconds:      "Milliseconds() / 1000",
+	timePrecisionMilliseconds: "Milliseconds()",
+	timePrecisionMicroseconds: "Microseconds()",
+	timePrecisionNanoseconds:  "Nanoseconds()",
+}
+
+// getAttributeValueSource returns the source code that converts the value of the field to the type expected by the
+// attribute constructor: it calls String for fmt.Stringer types, encodes time values in their precision,
+// and converts named and widened types.
+func getAttributeValueSource(f field, value string) string {
+	switch {
+	case f.named != nil && f.named.stringer:
+		return value + ".String()"
+	case f.timeKind == timeKindTimestamp:
+		return value + "." + timestampMethods[f.timePrecision]
+	case f.timeKind == timeKindDuration:
+		return value + "." + durationMethods[f.timePrecision]
+	case needsConversion(f):
+		return fmt.Sprintf("%s(%s)", getAttributeValueType(f.fieldType), value)
+	default:
+		return value
+	}
+}
+
+// timestampSources are the formats of the source code that converts the Unix time in the precision to time.Time.
+var timestampSources = map[timePrecision]string{
+	timePrecisionSeconds:      "time.Unix(%s, 0)",
+	timePrecisionMilliseconds: "time.UnixMilli(%s)",
+	timePrecisionMicroseconds: "time.UnixMicro(%s)",
+	timePrecisionNanoseconds:  "time.Unix(0, %s)",
+}
+
+// durationSources are the formats of the source code that converts the duration in the precision to time.Duration.
+var durationSources = map[timePrecision]string{
+	timePrecisionSeconds:      "time.Duration(%s) * time.Second",
+	timePrecisionMilliseconds: "time.Duration(%s) * time.Millisecond",
+	timePrecisionMicroseconds: "time.Duration(%s) * time.Microsecond",
+	timePrecisionNanoseconds:  "time.Duration(%s)",
+}
+
+// fromAttributesBuilder creates the fromAttributesFields of the structs of a package and collects the imports of
+// the packages of the types they use.
+type fromAttributesBuilder struct {
+	imports map[string]struct{}
+	// packagePath is the path of the package of the generated code.
+	packagePath string
+	// telemetryPackagePrefix is the prefix of the identifiers of the telemetry package in the generated code.
+	telemetryPackagePrefix string
+}
+
+// qualify returns the identifier of the package qualified for the generated code, and imports the package.
+func (b *fromAttributesBuilder) qualify(packagePath, name string) string {
+	switch packagePath {
+	case b.packagePath:
+		return name
+	case telemetryPackagePath:
+		return b.telemetryPackagePrefix + name
+	}
+
+	b.imports[packagePath] = struct{}{}
+
+	return getPackageName(packagePath) + "." + name
+}
+
+// getValueTypeName returns the Go type of the value of the field in the generated code.
+func (b *fromAttributesBuilder) getValueTypeName(f field) string {
+	switch {
+	case f.timeKind == timeKindTimestamp:
+		return b.qualify("time", "Time")
+	case f.timeKind == timeKindDuration:
+		return b.qualify("time", "Duration")
+	case f.named != nil:
+		return b.qualify(f.named.packagePath, f.named.name)
+	default:
+		return types.Typ[f.fieldType].Name()
+	}
+}
+
+// isRangeChecked returns true if the int64 attribute value must be checked to be in the range of the integer type
+// of the field before it is converted back, because the conversion would truncate it.
+func isRangeChecked(f field) bool {
+	if f.timeKind != timeKindNone {
+		return false
+	}
+
+	switch f.fieldType {
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Uint8, types.Uint16, types.Uint32:
+		return true
+	default:
+		return false
+	}
+}
+
+// getFieldValueSource returns the source code that converts the value of the type expected by the attribute
+// constructor back to the value of the field, which reverses getAttributeValueSource. The key is the source of
+// the key of the attribute, which is reported when the value is out of the range of the type of the field.
+func (b *fromAttributesBuilder) getFieldValueSource(f field, value, key string) string {
+	switch f.timeKind {
+	case timeKindTimestamp:
+		b.imports["time"] = struct{}{}
+		return fmt.Sprintf(timestampSources[f.timePrecision], value)
+	case timeKindDuration:
+		b.imports["time"] = struct{}{}
+		return fmt.Sprintf(durationSources[f.timePrecision], value)
+	case timeKindNone:
+	}
+
+	if isRangeChecked(f) {
+		return fmt.Sprintf(
+			"%s[%s](%s, %s)",
+			b.qualify(telemetryPackagePath, "ConvertAttributeInt"),
+			b.getValueTypeName(f),
+			key,
+			value,
+		)
+	}
+
+	if needsConversion(f) {
+		return fmt.Sprintf("%s(%s)", b.getValueTypeName(f), value)
+	}
+
+	return value
+}
+
+// createFromAttributesField creates the fromAttributesField that sets the field from its attributes.
+func (b *fromAttributesBuilder) createFromAttributesField(f field) fromAttributesField {
+	if f.embeddedStruct {
+		return fromAttributesField{
+			Name:         f.name,
+			EmbeddedFunc: b.qualify(f.embeddedStructPackage, f.name+"FromAttributes"),
+		}
+	}
+
+	attributeType := getAttributeType(f.fieldType)
+
+	ff := fromAttributesField{
+		Name:      f.name,
+		Key:       f.attributeKey(),
+		ValueType: strings.ToUpper(attributeType),
+		Checked:   isRangeChecked(f),
+	}
+
+	key := strconv.Quote(f.attributeKey())
+
+	switch {
+	case f.named != nil && f.named.stringer:
+		ff.Unsupported = "values of fmt.Stringer types can't be converted back"
+	case f.stringMap:
+		ff.MapValueType = b.getValueTypeName(f)
+		mapKey := fmt.Sprintf("attribute.Key(%s + k)", strconv.Quote(f.attributeKey()+"."))
+		ff.ValueSource = b.getFieldValueSource(f, fmt.Sprintf("v.As%s()", attributeType), mapKey)
+	case f.slice:
+		ff.ValueType += "SLICE"
+		ff.Required = !f.omitEmpty
+
+		if needsConversion(f) {
+			ff.SliceSource = fmt.Sprintf("v.As%sSlice()", attributeType)
+			ff.SliceValueType = b.getValueTypeName(f)
+			ff.ValueSource = b.getFieldValueSource(f, "e", key)
+		} else {
+			ff.ValueSource = fmt.Sprintf("v.As%sSlice()", attributeType)
+		}
+	default:
+		ff.ValueSource = b.getFieldValueSource(f, fmt.Sprintf("v.As%s()", attributeType), key)
+		ff.Pointer = f.pointer
+		// the attributes of pointers and omitted zero values can be missing
+		ff.Required = !f.pointer && !isZeroValueOmitted(f)
+	}
+
+	return ff
+}
+
+// getImports returns the sorted paths of the imported packages of the standard library and the other imported
+// packages.
+func (b *fromAttributesBuilder) getImports() (standard, other []string) {
+	for _, path := range slices.Sorted(maps.Keys(b.imports)) {
+		// the paths of the packages of the standard library don't start with a domain name
+		if strings.Contains(strings.Split(path, "/")[0], ".") {
+			other = append(other, path)
+		} else {
+			standard = append(standard, path)
+		}
+	}
+
+	return standard, other
+}
+
 type codeGenConfig struct {
-	packagePath    string
-	typeName       string
-	schemeDataType string
-	buildTags      string
+	packagePath string
+	buildTags   string
+	// template is the custom template of the code, which receives a codeGen. Empty means the default template.
+	template string
+	types    []codeGenType
+}
+
+// codeGenType is a struct to generate code for.
+type codeGenType struct {
+	typeName string
+	// schemeNamespace is the namespace of the schemes of the struct, which is also the package of its Protobuf schema.
+	schemeNamespace string
+	schemeDataType  string
+	// schemeEnvelope is the envelope of the records of the schemes of the struct.
+	schemeEnvelope schemeEnvelope
 	fields         []field
 }
 
-func generateCode(writer io.Writer, cfg codeGenConfig) error {
-	codeFields := make([]codeField, 0, len(cfg.fields))
+// getZeroValueCondition returns the condition that is true when the value of the field is not the zero value.
+// Values of fmt.Stringer types are zero when String returns an empty string.
+func getZeroValueCondition(f field, value string) string {
+	switch {
+	case f.slice:
+		return fmt.Sprintf("len(%s) > 0", value)
+	case f.named != nil && f.named.stringer:
+		return value + `.String() != ""`
+	case f.timeKind == timeKindTimestamp:
+		return fmt.Sprintf("!%s.IsZero()", value)
+	case f.fieldType == types.String:
+		return value + ` != ""`
+	case f.fieldType == types.Bool:
+		return value
+	default:
+		return value + " != 0"
+	}
+}
+
+// isZeroValueOmitted returns true when the attribute of the field is omitted for the zero value of the field,
+// either because of the omitempty option or because the zero value isn't valid in the schemes: the zero time means
+// the time is unknown, and the empty string is not a symbol of an enum.
+func isZeroValueOmitted(f field) bool {
+	if f.omitEmpty {
+		return true
+	}
 
-	for _, f := range cfg.fields {
-		var cf codeField
+	return !f.slice && !f.stringMap && (f.timeKind == timeKindTimestamp || isAvroEnum(f))
+}
+
+// createCodeField creates the codeField that adds the attributes of the field.
+func createCodeField(f field) codeField {
+	key := f.attributeKey()
+
+	switch {
+	case f.embeddedStruct:
+		return codeField{
+			AttributesSource: fmt.Sprintf(`d.%s.Attributes()...`, f.name),
+		}
+	case f.stringMap:
+		// empty maps don't have attributes, so omitEmpty has no effect
+		return codeField{
+			AttributesSource: fmt.Sprintf(
+				`attribute.%s("%s."+k, %s)`,
+				getAttributeType(f.fieldType),
+				key,
+				getAttributeValueSource(f, fmt.Sprintf("d.%s[k]", f.name)),
+			),
+			MapField: f.name,
+		}
+	case f.pointer:
+		value := "*d." + f.name
+		if isConvertedByMethod(f) {
+			// the method is called on the pointer directly
+			value = "d." + f.name
+		}
+
+		condition := fmt.Sprintf("d.%s != nil", f.name)
 
 		switch {
-		case f.embeddedStruct:
-			cf = codeField{
-				AttributesSource: fmt.Sprintf(`d.%s.Attributes()...`, f.name),
-			}
-		case f.slice:
-			cf = codeField{
-				AttributesSource: fmt.Sprintf(`attribute.%sSlice("%s", d.%s)`, getAttributeType(f.fieldType), f.name, f.name),
-			}
-		default:
-			cf = codeField{
-				AttributesSource: fmt.Sprintf(`attribute.%s("%s", d.%s)`, getAttributeType(f.fieldType), f.name, f.name),
-			}
+		case f.timeKind == timeKindTimestamp:
+			condition += fmt.Sprintf(" && !d.%s.IsZero()", f.name)
+		case isAvroEnum(f):
+			condition += fmt.Sprintf(` && *d.%s != ""`, f.name)
 		}
 
-		codeFields = append(codeFields, cf)
+		return codeField{
+			AttributesSource: fmt.Sprintf(
+				`attribute.%s("%s", %s)`,
+				getAttributeType(f.fieldType),
+				key,
+				getAttributeValueSource(f, value),
+			),
+			Condition: condition,
+		}
 	}
 
+	var cf codeField
+
+	switch {
+	case f.slice && needsConversion(f):
+		cf = codeField{
+			AttributesSource: fmt.Sprintf(`attribute.%sSlice("%s", values)`, getAttributeType(f.fieldType), key),
+			SliceField:       f.name,
+			SliceValueType:   getAttributeValueType(f.fieldType),
+			SliceValueSource: getAttributeValueSource(f, "v"),
+		}
+	case f.slice:
+		cf = codeField{
+			AttributesSource: fmt.Sprintf(`attribute.%sSlice("%s", d.%s)`, getAttributeType(f.fieldType), key, f.name),
+		}
+	default:
+		cf = codeField{
+			AttributesSource: fmt.Sprintf(
+				`attribute.%s("%s", %s)`,
+				getAttributeType(f.fieldType),
+				key,
+				getAttributeValueSource(f, "d."+f.name),
+			),
+		}
+	}
+
+	if isZeroValueOmitted(f) {
+		cf.Condition = getZeroValueCondition(f, "d."+f.name)
+	}
+
+	return cf
+}
+
+// generateCode generates the Attributes methods and the FromAttributes functions for the structs of the same package
+// into a single file.
+func generateCode(writer io.Writer, cfg codeGenConfig) error {
 	const alias = "ngxTelemetry"
 
 	var (
@@ -133,30 +748,113 @@ func generateCode(writer io.Writer, cfg codeGenConfig) error {
 		}
 	}
 
+	codeTypes := make([]codeType, 0, len(cfg.types))
+
+	builder := fromAttributesBuilder{
+		imports:                make(map[string]struct{}),
+		packagePath:            cfg.packagePath,
+		telemetryPackagePrefix: exportablePkgPrefix,
+	}
+
+	for _, t := range cfg.types {
+		codeFields := make([]codeField, 0, len(t.fields))
+		fromAttributesFields := make([]fromAttributesField, 0, len(t.fields))
+		var validationRules []validationRule
+
+		for _, f := range t.fields {
+			codeFields = append(codeFields, createCodeField(f))
+			fromAttributesFields = append(fromAttributesFields, builder.createFromAttributesField(f))
+			validationRules = append(validationRules, createValidationRules(f)...)
+
+			// the attributes of maps are added in the sorted order of their keys
+			if f.stringMap {
+				builder.imports["maps"] = struct{}{}
+				builder.imports["slices"] = struct{}{}
+			}
+		}
+
+		codeTypes = append(codeTypes, codeType{
+			StructName:           t.typeName,
+			SchemeDataType:       t.schemeDataType,
+			DataTypeKey:          t.schemeEnvelope.getDataTypeField(),
+			Fields:               codeFields,
+			FromAttributesFields: fromAttributesFields,
+			ValidationRules:      validationRules,
+			TelemetryFields:      getTelemetryFields(t.typeName, t.fields),
+		})
+
+		if len(validationRules) > 0 {
+			builder.imports["errors"] = struct{}{}
+		}
+	}
+
+	standardImports, imports := builder.getImports()
+
 	cg := codeGen{
 		PackageName:             getPackageName(cfg.packagePath),
 		ExportablePackagePrefix: exportablePkgPrefix,
 		TelemetryPackageAlias:   telemetryPkgAlias,
 		TelemetryPackagePath:    telemetryPkg,
-		StructName:              cfg.typeName,
-		SchemeDataType:          cfg.schemeDataType,
-		Fields:                  codeFields,
+		StandardImports:         standardImports,
+		Imports:                 imports,
+		Types:                   codeTypes,
 		BuildTags:               cfg.buildTags,
 	}
 
-	funcMap := template.FuncMap{
-		"getAttributeType": getAttributeType,
+	tmpl, err := parseTemplate("code", codeTemplate, cfg.template)
+	if err != nil {
+		return err
 	}
 
-	tmpl := template.Must(template.New("scheme").Funcs(funcMap).Parse(codeTemplate))
+	var buf bytes.Buffer
 
-	if err := tmpl.Execute(writer, cg); err != nil {
+	if err := tmpl.Execute(&buf, cg); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
 	}
 
+	code, err := formatCode(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	if _, err := writer.Write(code); err != nil {
+		return fmt.Errorf("failed to write code: %w", err)
+	}
+
 	return nil
 }
 
+// formatCode formats the code like gofmt and groups its imports like goimports, without adding or removing imports.
+// If the code is invalid, the error includes the code with line numbers, so that the invalid line can be found.
+func formatCode(code []byte) ([]byte, error) {
+	options := &imports.Options{
+		Comments:   true,
+		TabIndent:  true,
+		TabWidth:   8,
+		FormatOnly: true,
+	}
+
+	formatted, err := imports.Process("", code, options)
+	if err != nil {
+		return nil, fmt.Errorf("generated code is not valid Go: %w\n%s", err, numberLines(code))
+	}
+
+	return formatted, nil
+}
+
+// numberLines prefixes each line of the code with its number.
+func numberLines(code []byte) string {
+	lines := strings.Split(strings.TrimSuffix(string(code), "\n"), "\n")
+
+	var sb strings.Builder
+
+	for i, line := range lines {
+		fmt.Fprintf(&sb, "%4d  %s\n", i+1, line)
+	}
+
+	return sb.String()
+}
+
 func getPackageName(packagePath string) string {
 	packageParts := strings.Split(packagePath, "/")
 	return packageParts[len(packageParts)-1]
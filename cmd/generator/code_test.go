@@ -4,6 +4,8 @@ package main
 
 import (
 	"bytes"
+	"go/format"
+	"go/types"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -17,7 +19,7 @@ func TestGenerateCode(t *testing.T) {
 
 	cfg := parsingConfig{
 		pkgName:     "tests",
-		typeName:    "Data",
+		typeNames:   []string{"Data"},
 		loadPattern: "github.com/nginx/telemetry-exporter/cmd/generator/tests",
 		buildFlags:  []string{"-tags=generator"},
 	}
@@ -32,11 +34,420 @@ func TestGenerateCode(t *testing.T) {
 
 	codeCfg := codeGenConfig{
 		packagePath: pResult.packagePath,
-		typeName:    "Data",
-		fields:      pResult.fields,
+		types: []codeGenType{
+			{
+				typeName: "Data",
+				fields:   pResult.types[0].fields,
+			},
+		},
 	}
 
 	g.Expect(generateCode(&buf, codeCfg)).To(Succeed())
 
 	g.Expect(buf.Bytes()).ToNot(BeEmpty())
+
+	formatted, err := format.Source(buf.Bytes())
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(buf.String()).To(Equal(string(formatted)))
+}
+
+func TestGenerateCodeDataTypeAttribute(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		dataType   string
+		unexpected string
+		expected   string
+		envelope   schemeEnvelope
+	}{
+		{
+			name:     "default envelope",
+			dataType: "product-telemetry",
+			expected: `attribute.String("dataType", "product-telemetry")`,
+		},
+		{
+			name:     "data type field of custom envelope",
+			dataType: "product-telemetry",
+			envelope: schemeEnvelope{
+				Fields: []envelopeField{
+					{Name: "timestamp", Type: "long"},
+					{Name: "kind", Type: "string", DataType: true},
+				},
+			},
+			expected:   `attribute.String("kind", "product-telemetry")`,
+			unexpected: `"dataType"`,
+		},
+		{
+			name:     "custom envelope without data type field",
+			dataType: "product-telemetry",
+			envelope: schemeEnvelope{
+				Fields: []envelopeField{{Name: "timestamp", Type: "long"}},
+			},
+			unexpected: "product-telemetry",
+		},
+		{
+			name:       "no data type",
+			unexpected: `"dataType"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			codeCfg := codeGenConfig{
+				packagePath: "example.com/telemetry",
+				types: []codeGenType{
+					{
+						typeName:       "Data",
+						schemeDataType: test.dataType,
+						schemeEnvelope: test.envelope,
+						fields: []field{
+							{docString: "Name is a name.", name: "Name", key: "Name", fieldType: types.String},
+						},
+					},
+				},
+			}
+
+			var buf bytes.Buffer
+
+			g.Expect(generateCode(&buf, codeCfg)).To(Succeed())
+
+			if test.expected != "" {
+				g.Expect(buf.String()).To(ContainSubstring(test.expected))
+			}
+
+			if test.unexpected != "" {
+				g.Expect(buf.String()).ToNot(ContainSubstring(test.unexpected))
+			}
+		})
+	}
+}
+
+func TestFormatCode(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	code := `//go:build generator
+package tests
+import (
+	"go.opentelemetry.io/otel/attribute"
+	"github.com/nginx/telemetry-exporter/pkg/telemetry"
+	"slices"
+)
+func (d *Data) Attributes() []attribute.KeyValue {
+  return nil
+}
+var _ telemetry.Exportable = (*Data)(nil)
+var _ = slices.Sorted[string]
+`
+
+	expected := `//go:build generator
+
+package tests
+
+import (
+	"slices"
+
+	"github.com/nginx/telemetry-exporter/pkg/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func (d *Data) Attributes() []attribute.KeyValue {
+	return nil
+}
+
+var _ telemetry.Exportable = (*Data)(nil)
+var _ = slices.Sorted[string]
+`
+
+	formatted, err := formatCode([]byte(code))
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(formatted)).To(Equal(expected))
+
+	_, err = formatCode([]byte("package tests\n\nfunc (d *Data) Attributes() {\n"))
+
+	g.Expect(err).To(MatchError(ContainSubstring("generated code is not valid Go")))
+	g.Expect(err).To(MatchError(ContainSubstring("   3  func (d *Data) Attributes() {")))
+}
+
+func TestGetAttributeValueSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected string
+		field    field
+	}{
+		{
+			name:     "int64",
+			field:    field{fieldType: types.Int64},
+			expected: "d.Field",
+		},
+		{
+			name:     "widened int32",
+			field:    field{fieldType: types.Int32},
+			expected: "int64(d.Field)",
+		},
+		{
+			name:     "named string",
+			field:    field{fieldType: types.String, named: &namedType{name: "Platform"}},
+			expected: "string(d.Field)",
+		},
+		{
+			name:     "stringer",
+			field:    field{fieldType: types.String, named: &namedType{name: "Level", stringer: true}},
+			expected: "d.Field.String()",
+		},
+		{
+			name: "timestamp in seconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionSeconds,
+			},
+			expected: "d.Field.Unix()",
+		},
+		{
+			name: "timestamp in nanoseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionNanoseconds,
+			},
+			expected: "d.Field.UnixNano()",
+		},
+		{
+			name: "duration in seconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindDuration,
+				timePrecision: timePrecisionSeconds,
+			},
+			expected: "d.Field.Milliseconds() / 1000",
+		},
+		{
+			name: "duration in microseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindDuration,
+				timePrecision: timePrecisionMicroseconds,
+			},
+			expected: "d.Field.Microseconds()",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(getAttributeValueSource(test.field, "d.Field")).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCreateCodeFieldCondition(t *testing.T) {
+	t.Parallel()
+
+	platform := &namedType{name: "Platform", enumValues: []string{"aws", "gcp"}}
+
+	tests := []struct {
+		name     string
+		expected string
+		field    field
+	}{
+		{
+			name:     "string",
+			field:    field{name: "Field", fieldType: types.String},
+			expected: "",
+		},
+		{
+			name:     "omitted empty int",
+			field:    field{name: "Field", fieldType: types.Int64, omitEmpty: true},
+			expected: "d.Field != 0",
+		},
+		{
+			name:     "timestamp",
+			field:    field{name: "Field", fieldType: types.Int64, timeKind: timeKindTimestamp},
+			expected: "!d.Field.IsZero()",
+		},
+		{
+			name:     "enum",
+			field:    field{name: "Field", fieldType: types.String, named: platform},
+			expected: `d.Field != ""`,
+		},
+		{
+			name:     "enum pointer",
+			field:    field{name: "Field", fieldType: types.String, named: platform, pointer: true},
+			expected: `d.Field != nil && *d.Field != ""`,
+		},
+		{
+			name:     "enum slice",
+			field:    field{name: "Field", fieldType: types.String, named: platform, slice: true},
+			expected: "",
+		},
+		{
+			name:     "named string without constants",
+			field:    field{name: "Field", fieldType: types.String, named: &namedType{name: "Name"}},
+			expected: "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(createCodeField(test.field).Condition).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestGetFieldValueSource(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		expected        string
+		expectedImports []string
+		field           field
+	}{
+		{
+			name:     "int64",
+			field:    field{fieldType: types.Int64},
+			expected: "v",
+		},
+		{
+			name:     "range-checked int32",
+			field:    field{fieldType: types.Int32},
+			expected: `telemetry.ConvertAttributeInt[int32]("key", v)`,
+		},
+		{
+			name: "range-checked named uint8",
+			field: field{
+				fieldType: types.Uint8,
+				named:     &namedType{packagePath: "example.com/tests", name: "Level"},
+			},
+			expected: `telemetry.ConvertAttributeInt[Level]("key", v)`,
+		},
+		{
+			name:     "widened float32",
+			field:    field{fieldType: types.Float32},
+			expected: "float32(v)",
+		},
+		{
+			name: "named string of the package",
+			field: field{
+				fieldType: types.String,
+				named:     &namedType{packagePath: "example.com/tests", name: "Platform"},
+			},
+			expected: "Platform(v)",
+		},
+		{
+			name: "named string of another package",
+			field: field{
+				fieldType: types.String,
+				named:     &namedType{packagePath: "example.com/tests/platforms", name: "Platform"},
+			},
+			expected:        "platforms.Platform(v)",
+			expectedImports: []string{"example.com/tests/platforms"},
+		},
+		{
+			name: "timestamp in seconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionSeconds,
+			},
+			expected:        "time.Unix(v, 0)",
+			expectedImports: []string{"time"},
+		},
+		{
+			name: "timestamp in nanoseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionNanoseconds,
+			},
+			expected:        "time.Unix(0, v)",
+			expectedImports: []string{"time"},
+		},
+		{
+			name: "duration in microseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindDuration,
+				timePrecision: timePrecisionMicroseconds,
+			},
+			expected:        "time.Duration(v) * time.Microsecond",
+			expectedImports: []string{"time"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			builder := fromAttributesBuilder{
+				imports:                make(map[string]struct{}),
+				packagePath:            "example.com/tests",
+				telemetryPackagePrefix: "telemetry.",
+			}
+
+			g.Expect(builder.getFieldValueSource(test.field, "v", `"key"`)).To(Equal(test.expected))
+
+			standard, other := builder.getImports()
+			g.Expect(append(standard, other...)).To(Equal(test.expectedImports))
+		})
+	}
+}
+
+func TestGetGoType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected string
+		field    field
+	}{
+		{
+			name:     "basic",
+			field:    field{fieldType: types.Uint16},
+			expected: "uint16",
+		},
+		{
+			name: "pointer to named type",
+			field: field{
+				fieldType: types.Int32,
+				named:     &namedType{packagePath: "example.com/tests", name: "Count"},
+				pointer:   true,
+			},
+			expected: "*tests.Count",
+		},
+		{
+			name:     "slice of durations",
+			field:    field{fieldType: types.Int64, timeKind: timeKindDuration, slice: true},
+			expected: "[]time.Duration",
+		},
+		{
+			name:     "map of timestamps",
+			field:    field{fieldType: types.Int64, timeKind: timeKindTimestamp, stringMap: true},
+			expected: "map[string]time.Time",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(getGoType(test.field)).To(Equal(test.expected))
+		})
+	}
 }
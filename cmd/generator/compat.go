@@ -0,0 +1,361 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// compatCommand is the subcommand that checks the compatibility of the current schemes with the previously generated
+// ones, instead of generating the outputs.
+const compatCommand = "compat"
+
+// compatibility is the direction in which the current scheme must be compatible with the previous scheme,
+// according to the Avro schema resolution.
+type compatibility string
+
+const (
+	// compatibilityBackward requires that the current scheme can read the data written with the previous scheme.
+	compatibilityBackward compatibility = "backward"
+	// compatibilityForward requires that the previous scheme can read the data written with the current scheme.
+	compatibilityForward compatibility = "forward"
+	// compatibilityFull requires both backward and forward compatibility.
+	compatibilityFull compatibility = "full"
+)
+
+// compatibilities are the supported compatibilities.
+var compatibilities = []compatibility{
+	compatibilityBackward,
+	compatibilityForward,
+	compatibilityFull,
+}
+
+// avroPromotions are the types that the data of a type can be read as, according to the Avro schema resolution.
+var avroPromotions = map[string][]string{
+	"int":    {"long", "float", "double"},
+	"long":   {"float", "double"},
+	"float":  {"double"},
+	"string": {"bytes"},
+	"bytes":  {"string"},
+}
+
+// canResolve returns true if the data written with the writer schema can be read with the reader schema,
+// according to the Avro schema resolution. Unlike in the resolution, the logical types must match, because
+// the data would be misinterpreted otherwise.
+func canResolve(writer, reader *avroSchema) bool {
+	if reader.kind == "union" || writer.kind == "union" {
+		return canResolveUnion(writer, reader)
+	}
+
+	if writer.kind != reader.kind {
+		return writer.logicalType == "" && reader.logicalType == "" &&
+			slices.Contains(avroPromotions[writer.kind], reader.kind)
+	}
+
+	switch reader.kind {
+	case "array", "map":
+		return canResolve(writer.items, reader.items)
+	case "enum":
+		if shortAvroName(writer.name) != shortAvroName(reader.name) {
+			return false
+		}
+
+		for _, symbol := range writer.symbols {
+			if !slices.Contains(reader.symbols, symbol) {
+				return false
+			}
+		}
+
+		return true
+	case "record":
+		return shortAvroName(writer.name) == shortAvroName(reader.name) && canResolveFields(writer, reader)
+	default:
+		return writer.logicalType == reader.logicalType
+	}
+}
+
+// canResolveUnion resolves the schemas when either of them is a union: every branch of a writer union must be
+// readable by the reader, and a reader union must have a branch that can read the writer.
+func canResolveUnion(writer, reader *avroSchema) bool {
+	if writer.kind == "union" {
+		for _, branch := range writer.branches {
+			if !canResolve(branch, reader) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, branch := range reader.branches {
+		if canResolve(writer, branch) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// splitNullable returns true if the schema is a union with null, and the schema without null.
+func splitNullable(schema *avroSchema) (bool, *avroSchema) {
+	if schema.kind != "union" {
+		return false, schema
+	}
+
+	nonNull := make([]*avroSchema, 0, len(schema.branches))
+	for _, branch := range schema.branches {
+		if branch.kind != "null" {
+			nonNull = append(nonNull, branch)
+		}
+	}
+
+	nullable := len(nonNull) < len(schema.branches)
+
+	if len(nonNull) == 1 {
+		return nullable, nonNull[0]
+	}
+
+	return nullable, &avroSchema{kind: "union", branches: nonNull}
+}
+
+// checkCompatibility returns the breaking changes of the current record compared to the previous record, according to
+// the Avro schema resolution in the direction of the compatibility. A removed field is always a breaking change, even
+// though the Avro schema resolution ignores it when the current scheme is the reader, because its data is no longer
+// ingested.
+func checkCompatibility(previous, current *avroSchema, compat compatibility) []string {
+	var changes []string
+
+	if previous.name != current.name {
+		changes = append(changes, fmt.Sprintf("record %s was renamed to %s", previous.name, current.name))
+	}
+
+	currentFields := make(map[string]avroSchemaField, len(current.fields))
+	for _, f := range current.fields {
+		currentFields[f.name] = f
+	}
+
+	previousFields := make(map[string]struct{}, len(previous.fields))
+
+	for _, prev := range previous.fields {
+		previousFields[prev.name] = struct{}{}
+
+		cur, exists := currentFields[prev.name]
+		if !exists {
+			changes = append(changes, fmt.Sprintf("field %s was removed", prev.name))
+			continue
+		}
+
+		changes = append(changes, checkField(prev.name, prev.schema, cur.schema, compat)...)
+	}
+
+	// the previous scheme ignores the added fields when it is the reader
+	if compat == compatibilityForward {
+		return changes
+	}
+
+	for _, cur := range current.fields {
+		if _, exists := previousFields[cur.name]; !exists && !cur.hasDefault {
+			changes = append(changes, fmt.Sprintf("field %s was added without a default value", cur.name))
+		}
+	}
+
+	return changes
+}
+
+// canResolveFields returns true if the fields of the writer record can be read with the fields of the reader record.
+// The fields of the writer that the reader doesn't have are ignored, and the fields of the reader that the writer
+// doesn't have must have a default value.
+func canResolveFields(writer, reader *avroSchema) bool {
+	writerFields := make(map[string]avroSchemaField, len(writer.fields))
+	for _, f := range writer.fields {
+		writerFields[f.name] = f
+	}
+
+	for _, r := range reader.fields {
+		w, exists := writerFields[r.name]
+		if !exists {
+			if !r.hasDefault {
+				return false
+			}
+
+			continue
+		}
+
+		if !canResolve(w.schema, r.schema) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// checkField returns the breaking changes of the schema of the field in the direction of the compatibility.
+// For the full compatibility, the changes that are found in both directions are only reported once.
+func checkField(name string, previous, current *avroSchema, compat compatibility) []string {
+	var changes []string
+
+	if compat != compatibilityForward {
+		changes = resolution{field: name, backward: true}.check("field "+name, previous, current)
+	}
+
+	if compat == compatibilityBackward {
+		return changes
+	}
+
+	for _, change := range (resolution{field: name}).check("field "+name, current, previous) {
+		if !slices.Contains(changes, change) {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// resolution checks whether the data of a field written with the writer schema can be read with the reader schema,
+// describing the breaking changes from the previous to the current schema.
+type resolution struct {
+	// field is the name of the field.
+	field string
+	// backward is true when the previous schema is the writer schema, and false when it is the reader schema.
+	backward bool
+}
+
+// check returns the breaking changes of the schema of the subject, like a field or the items of a field.
+// The items of arrays, the values of maps and the branches of unions are checked recursively, so that the changes
+// are reported where they are instead of as a change of the whole type.
+func (r resolution) check(subject string, writer, reader *avroSchema) []string {
+	if canResolve(writer, reader) {
+		return nil
+	}
+
+	var changes []string
+
+	writerNullable, writerType := splitNullable(writer)
+	readerNullable, readerType := splitNullable(reader)
+
+	if writerNullable && !readerNullable {
+		if r.backward {
+			changes = append(changes, subject+" is no longer nullable")
+		} else {
+			changes = append(changes, subject+" became nullable")
+		}
+	}
+
+	if canResolve(writerType, readerType) {
+		return changes
+	}
+
+	previous, current := writerType, readerType
+	if !r.backward {
+		previous, current = readerType, writerType
+	}
+
+	typeChange := fmt.Sprintf("type of %s changed from %s to %s", subject, previous, current)
+
+	if writerType.kind != readerType.kind || shortAvroName(writerType.name) != shortAvroName(readerType.name) {
+		return append(changes, typeChange)
+	}
+
+	switch writerType.kind {
+	case "array":
+		return append(changes, r.check("items of "+subject, writerType.items, readerType.items)...)
+	case "map":
+		return append(changes, r.check("values of "+subject, writerType.items, readerType.items)...)
+	case "union":
+		return append(changes, r.checkUnion(subject, writerType, readerType)...)
+	case "enum":
+		return append(changes, r.checkEnum(writerType, readerType)...)
+	default:
+		return append(changes, typeChange)
+	}
+}
+
+// checkUnion returns the breaking changes of the branches of the writer union that the reader union can't read.
+// A branch is compared with the branch of the reader of the same type.
+func (r resolution) checkUnion(subject string, writer, reader *avroSchema) []string {
+	var changes []string
+
+	for _, w := range writer.branches {
+		if canResolve(w, reader) {
+			continue
+		}
+
+		i := slices.IndexFunc(reader.branches, func(b *avroSchema) bool {
+			return b.kind == w.kind && shortAvroName(b.name) == shortAvroName(w.name)
+		})
+
+		switch {
+		case i >= 0:
+			changes = append(changes, r.check(subject, w, reader.branches[i])...)
+		case r.backward:
+			changes = append(changes, fmt.Sprintf("type %s was removed from the union of %s", w, subject))
+		default:
+			changes = append(changes, fmt.Sprintf("type %s was added to the union of %s", w, subject))
+		}
+	}
+
+	return changes
+}
+
+// checkEnum returns the breaking changes of the symbols of the writer enum that the reader enum doesn't have.
+func (r resolution) checkEnum(writer, reader *avroSchema) []string {
+	var changes []string
+
+	for _, symbol := range writer.symbols {
+		if slices.Contains(reader.symbols, symbol) {
+			continue
+		}
+
+		if r.backward {
+			changes = append(changes, fmt.Sprintf("symbol %s was removed from enum %s of field %s",
+				symbol, shortAvroName(writer.name), r.field))
+		} else {
+			changes = append(changes, fmt.Sprintf("symbol %s was added to enum %s of field %s",
+				symbol, shortAvroName(writer.name), r.field))
+		}
+	}
+
+	return changes
+}
+
+// loadPreviousScheme loads the record of the previously generated Avro JSON schema.
+func loadPreviousScheme(path string) (*avroSchema, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // the path of the scheme is set by the user
+	if err != nil {
+		return nil, fmt.Errorf("failed to read previous scheme: %w", err)
+	}
+
+	record, err := parseAvsc(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse previous scheme %s: %w", path, err)
+	}
+
+	return record, nil
+}
+
+// checkSchemeCompatibility returns the breaking changes of the scheme compared to the previous Avro JSON schema in
+// the file, in the direction of the compatibility.
+func checkSchemeCompatibility(previousPath string, cfg schemeGenConfig, compat compatibility) ([]string, error) {
+	previous, err := loadPreviousScheme(previousPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// the current record is parsed from its generated Avro JSON schema, like the previous one, so that they're
+	// comparable
+	var buf bytes.Buffer
+
+	if err := generateAvsc(&buf, cfg); err != nil {
+		return nil, fmt.Errorf("failed to generate scheme: %w", err)
+	}
+
+	current, err := parseAvsc(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse current scheme: %w", err)
+	}
+
+	return checkCompatibility(previous, current, compat), nil
+}
@@ -0,0 +1,290 @@
+//go:build generator
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	t.Parallel()
+
+	const previous = `{
+  "type": "record",
+  "name": "Data",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {"name": "dataType", "type": "string"},
+    {"name": "Count", "type": ["null", "long"], "default": null},
+    {"name": "Name", "type": ["null", "string"], "default": null},
+    {
+      "name": "Platform",
+      "type": ["null", {"type": "enum", "name": "Platform", "symbols": ["aws", "gcp"]}],
+      "default": null
+    },
+    {"name": "Time", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}], "default": null},
+    {"name": "Ratios", "type": ["null", {"type": "array", "items": "float"}], "default": null}
+  ]
+}`
+
+	// breaking has the breaking changes of both directions
+	const breaking = `{
+  "type": "record",
+  "name": "Datum",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {"name": "dataType", "type": ["null", "string"], "default": null},
+    {"name": "Added", "type": "boolean"},
+    {"name": "Count", "type": ["null", "double"], "default": null},
+    {
+      "name": "Platform",
+      "type": ["null", {"type": "enum", "name": "Platform", "symbols": ["aws", "azure"]}],
+      "default": null
+    },
+    {"name": "Time", "type": ["null", {"type": "long", "logicalType": "timestamp-micros"}], "default": null},
+    {"name": "Ratios", "type": {"type": "array", "items": "double"}}
+  ]
+}`
+
+	tests := []struct {
+		name            string
+		current         string
+		compat          compatibility
+		expectedChanges []string
+	}{
+		{
+			name:    "same",
+			current: previous,
+			compat:  compatibilityFull,
+		},
+		{
+			name:   "added nullable field",
+			compat: compatibilityFull,
+			current: `{
+  "type": "record",
+  "name": "Data",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {"name": "dataType", "type": "string"},
+    {"name": "Added", "type": ["null", "boolean"], "default": null},
+    {"name": "Count", "type": ["null", "long"], "default": null},
+    {"name": "Name", "type": ["null", "string"], "default": null},
+    {
+      "name": "Platform",
+      "type": ["null", {"type": "enum", "name": "Platform", "symbols": ["aws", "gcp"]}],
+      "default": null
+    },
+    {"name": "Time", "type": ["null", {"type": "long", "logicalType": "timestamp-millis"}], "default": null},
+    {"name": "Ratios", "type": ["null", {"type": "array", "items": "float"}], "default": null}
+  ]
+}`,
+		},
+		{
+			name:    "breaking changes in backward compatibility",
+			current: breaking,
+			compat:  compatibilityBackward,
+			expectedChanges: []string{
+				"record gateway.nginx.org.Data was renamed to gateway.nginx.org.Datum",
+				"field Name was removed",
+				"symbol gcp was removed from enum Platform of field Platform",
+				"type of field Time changed from long(timestamp-millis) to long(timestamp-micros)",
+				"field Ratios is no longer nullable",
+				"field Added was added without a default value",
+			},
+		},
+		{
+			name:    "breaking changes in forward compatibility",
+			current: breaking,
+			compat:  compatibilityForward,
+			expectedChanges: []string{
+				"record gateway.nginx.org.Data was renamed to gateway.nginx.org.Datum",
+				"field dataType became nullable",
+				"type of field Count changed from long to double",
+				"field Name was removed",
+				"symbol azure was added to enum Platform of field Platform",
+				"type of field Time changed from long(timestamp-millis) to long(timestamp-micros)",
+				"type of items of field Ratios changed from float to double",
+			},
+		},
+		{
+			name:    "breaking changes in full compatibility",
+			current: breaking,
+			compat:  compatibilityFull,
+			expectedChanges: []string{
+				"record gateway.nginx.org.Data was renamed to gateway.nginx.org.Datum",
+				"field dataType became nullable",
+				"type of field Count changed from long to double",
+				"field Name was removed",
+				"symbol gcp was removed from enum Platform of field Platform",
+				"symbol azure was added to enum Platform of field Platform",
+				"type of field Time changed from long(timestamp-millis) to long(timestamp-micros)",
+				"field Ratios is no longer nullable",
+				"type of items of field Ratios changed from float to double",
+				"field Added was added without a default value",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			previousRecord, err := parseAvsc([]byte(previous))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			currentRecord, err := parseAvsc([]byte(test.current))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(checkCompatibility(previousRecord, currentRecord, test.compat)).To(Equal(test.expectedChanges))
+		})
+	}
+}
+
+func TestCheckCompatibilityNestedTypes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	const previous = `{
+  "type": "record",
+  "name": "Data",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {
+      "name": "Platforms",
+      "type": ["null", {"type": "array", "items": {"type": "enum", "name": "Platform", "symbols": ["aws", "gcp"]}}],
+      "default": null
+    },
+    {"name": "Counts", "type": ["null", {"type": "map", "values": "long"}], "default": null},
+    {"name": "Labels", "type": ["null", {"type": "map", "values": "string"}], "default": null},
+    {"name": "Value", "type": ["null", "string", {"type": "array", "items": "long"}], "default": null}
+  ]
+}`
+
+	const current = `{
+  "type": "record",
+  "name": "Data",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {
+      "name": "Platforms",
+      "type": [
+        "null",
+        {"type": "array", "items": {"type": "enum", "name": "Platform", "symbols": ["aws", "azure", "gcp"]}}
+      ],
+      "default": null
+    },
+    {"name": "Counts", "type": ["null", {"type": "map", "values": "int"}], "default": null},
+    {"name": "Labels", "type": ["null", {"type": "map", "values": ["null", "string"]}], "default": null},
+    {"name": "Value", "type": ["null", "boolean", {"type": "array", "items": "int"}], "default": null}
+  ]
+}`
+
+	previousRecord, err := parseAvsc([]byte(previous))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	currentRecord, err := parseAvsc([]byte(current))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(checkCompatibility(previousRecord, currentRecord, compatibilityFull)).To(Equal([]string{
+		"symbol azure was added to enum Platform of field Platforms",
+		"type of values of field Counts changed from long to int",
+		"values of field Labels became nullable",
+		"type string was removed from the union of field Value",
+		"type of items of field Value changed from long to int",
+		"type boolean was added to the union of field Value",
+	}))
+}
+
+func TestCanResolve(t *testing.T) {
+	t.Parallel()
+
+	long := &avroSchema{kind: "long"}
+	double := &avroSchema{kind: "double"}
+	null := &avroSchema{kind: "null"}
+
+	tests := []struct {
+		writer   *avroSchema
+		reader   *avroSchema
+		name     string
+		expected bool
+	}{
+		{name: "same", writer: long, reader: long, expected: true},
+		{name: "promotion", writer: long, reader: double, expected: true},
+		{name: "no demotion", writer: double, reader: long, expected: false},
+		{
+			name:     "into union",
+			writer:   long,
+			reader:   &avroSchema{kind: "union", branches: []*avroSchema{null, long}},
+			expected: true,
+		},
+		{
+			name:     "from union",
+			writer:   &avroSchema{kind: "union", branches: []*avroSchema{null, long}},
+			reader:   long,
+			expected: false,
+		},
+		{
+			name:     "logical type",
+			writer:   &avroSchema{kind: "long", logicalType: "timestamp-millis"},
+			reader:   &avroSchema{kind: "double"},
+			expected: false,
+		},
+		{
+			name:     "enum with more symbols",
+			writer:   &avroSchema{kind: "enum", name: "a.E", symbols: []string{"A"}},
+			reader:   &avroSchema{kind: "enum", name: "b.E", symbols: []string{"A", "B"}},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(canResolve(test.writer, test.reader)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestCheckSchemeCompatibility(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := schemeGenConfig{
+		namespace:          "gateway.nginx.org",
+		protocol:           "NGFProductTelemetry",
+		dataFabricDataType: "ngf-product-telemetry",
+		record:             "Data",
+	}
+
+	dir := t.TempDir()
+
+	previousAvsc := `{
+  "type": "record",
+  "name": "Data",
+  "namespace": "gateway.nginx.org",
+  "fields": [
+    {"name": "dataType", "type": "string"},
+    {"name": "eventTime", "type": "long"},
+    {"name": "ingestTime", "type": "long"},
+    {"name": "Name", "type": ["null", "string"], "default": null}
+  ]
+}`
+	path := filepath.Join(dir, "data.avsc")
+	g.Expect(os.WriteFile(path, []byte(previousAvsc), 0o600)).To(Succeed())
+
+	changes, err := checkSchemeCompatibility(path, cfg, compatibilityBackward)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(changes).To(Equal([]string{"field Name was removed"}))
+
+	_, err = checkSchemeCompatibility(filepath.Join(dir, "missing.avsc"), cfg, compatibilityBackward)
+
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read previous scheme")))
+}
@@ -0,0 +1,259 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generatorConfig is the config file of the generator, in YAML or JSON.
+// It describes the packages to generate and their settings, so that the settings of large projects can be
+// managed in a single file. For example:
+//
+//	buildTags: generator
+//	scheme:
+//	  namespace: gateway.nginx.org
+//	packages:
+//	  - dir: internal/telemetry
+//	    output: telemetry_attributes_generated.go
+//	    types:
+//	      - name: Data
+//	        scheme:
+//	          protocol: NGFProductTelemetry
+//	          dataType: ngf-product-telemetry
+//	          output: data.avdl
+//
+// The settings of the config are the defaults of the corresponding flags, so the flags override them.
+// The settings of a package override the top-level settings.
+type generatorConfig struct {
+	// Packages are the packages to generate.
+	Packages          []packageConfig `yaml:"packages"`
+	generatorSettings `yaml:",inline"`
+}
+
+// generatorSettings are the settings that apply to all types of a package.
+type generatorSettings struct {
+	// BuildTags is the comma separated list of build tags. See the -build-tags flag.
+	BuildTags string `yaml:"buildTags"`
+	// KeyStyle is the style of the attribute keys. See the -key-style flag.
+	KeyStyle string `yaml:"keyStyle"`
+	// KeyPrefix is the prefix of the attribute keys. See the -key-prefix flag.
+	KeyPrefix string `yaml:"keyPrefix"`
+	// TimePrecision is the precision of the time fields. See the -time-precision flag.
+	TimePrecision string `yaml:"timePrecision"`
+	// DocsFormat is the format of the data dictionary. See the -docs-format flag.
+	DocsFormat string `yaml:"docsFormat"`
+	// Scheme are the scheme settings.
+	Scheme schemeSettings `yaml:"scheme"`
+	// Avsc enables the generation of the Avro JSON schema of the types whose scheme is generated.
+	// See the -avsc flag.
+	Avsc bool `yaml:"avsc"`
+	// JSONSchema enables the generation of the JSON Schema of the types. See the -json-schema flag.
+	JSONSchema bool `yaml:"jsonSchema"`
+	// Proto enables the generation of the Protobuf schema of the types. See the -proto flag.
+	Proto bool `yaml:"proto"`
+	// Docs enables the generation of the data dictionary of the types. See the -docs flag.
+	Docs bool `yaml:"docs"`
+}
+
+// schemeSettings are the settings of the scheme.
+type schemeSettings struct {
+	// Envelope is the envelope of the scheme records. See the -scheme-envelope flag, which overrides it.
+	Envelope *schemeEnvelope `yaml:"envelope"`
+	// Namespace is the scheme namespace. See the -scheme-namespace flag.
+	Namespace string `yaml:"namespace"`
+	// Protocol is the scheme protocol. See the -scheme-protocol flag.
+	Protocol string `yaml:"protocol"`
+	// DataType is the data fabric data type. See the -scheme-df-datatype flag.
+	DataType string `yaml:"dataType"`
+}
+
+// packageConfig is the config of a package.
+type packageConfig struct {
+	// Dir is the directory of the package, relative to the directory of the config file.
+	Dir string `yaml:"dir"`
+	// Output is the path of the generated code file, relative to the directory of the package, or - for stdout.
+	// See the -output flag.
+	Output string `yaml:"output"`
+	// Types are the types of the package to generate.
+	Types             []typeConfig `yaml:"types"`
+	generatorSettings `yaml:",inline"`
+}
+
+// typeConfig is the config of a type.
+type typeConfig struct {
+	// Name is the name of the type.
+	Name string `yaml:"name"`
+	// Scheme is the scheme config of the type. The scheme is generated when the protocol is set.
+	Scheme typeSchemeConfig `yaml:"scheme"`
+}
+
+// typeSchemeConfig is the scheme config of a type.
+type typeSchemeConfig struct {
+	// Output is the path of the generated scheme file, relative to the directory of the package, or - for stdout.
+	// See the -scheme-output flag.
+	Output string `yaml:"output"`
+	// AvscOutput is the path of the generated Avro JSON schema file, relative to the directory of the package,
+	// or - for stdout. See the -avsc-output flag.
+	AvscOutput string `yaml:"avscOutput"`
+	// JSONSchemaOutput is the path of the generated JSON Schema file, relative to the directory of the package,
+	// or - for stdout. See the -json-schema-output flag.
+	JSONSchemaOutput string `yaml:"jsonSchemaOutput"`
+	// ProtoOutput is the path of the generated Protobuf schema file, relative to the directory of the package,
+	// or - for stdout. The lock file is next to it. See the -proto-output flag.
+	ProtoOutput string `yaml:"protoOutput"`
+	// DocsOutput is the path of the generated data dictionary file, relative to the directory of the package,
+	// or - for stdout. See the -docs-output flag.
+	DocsOutput     string `yaml:"docsOutput"`
+	schemeSettings `yaml:",inline"`
+}
+
+// loadConfig loads the config file. Unknown fields are rejected.
+func loadConfig(path string) (generatorConfig, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // the path of the config is set by the user
+	if err != nil {
+		return generatorConfig{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return decodeConfig(bytes.NewReader(content))
+}
+
+// decodeConfig decodes the config from YAML or JSON, which is a subset of YAML.
+func decodeConfig(reader io.Reader) (generatorConfig, error) {
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	var cfg generatorConfig
+
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return generatorConfig{}, fmt.Errorf("failed to decode config: %w", err)
+	}
+
+	if err := cfg.validate(); err != nil {
+		return generatorConfig{}, fmt.Errorf("invalid config: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// validateEnvelope validates the envelope, which is nil when it is not set.
+func validateEnvelope(envelope *schemeEnvelope) error {
+	if envelope == nil {
+		return nil
+	}
+
+	if err := envelope.validate(); err != nil {
+		return fmt.Errorf("scheme envelope: %w", err)
+	}
+
+	return nil
+}
+
+func (c generatorConfig) validate() error {
+	if err := validateEnvelope(c.Scheme.Envelope); err != nil {
+		return err
+	}
+
+	dirs := make(map[string]struct{}, len(c.Packages))
+
+	for i, pkg := range c.Packages {
+		if pkg.Dir == "" {
+			return fmt.Errorf("package %d: dir is required", i)
+		}
+
+		if err := validateEnvelope(pkg.Scheme.Envelope); err != nil {
+			return fmt.Errorf("package %s: %w", pkg.Dir, err)
+		}
+
+		dir := filepath.Clean(pkg.Dir)
+		if _, exists := dirs[dir]; exists {
+			return fmt.Errorf("package %s: already exists", pkg.Dir)
+		}
+		dirs[dir] = struct{}{}
+
+		names := make(map[string]struct{}, len(pkg.Types))
+
+		for j, t := range pkg.Types {
+			if t.Name == "" {
+				return fmt.Errorf("package %s: type %d: name is required", pkg.Dir, j)
+			}
+
+			if _, exists := names[t.Name]; exists {
+				return fmt.Errorf("package %s: type %s: already exists", pkg.Dir, t.Name)
+			}
+			names[t.Name] = struct{}{}
+
+			if err := validateEnvelope(t.Scheme.Envelope); err != nil {
+				return fmt.Errorf("package %s: type %s: %w", pkg.Dir, t.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// findPackage returns the config of the package in the directory, where configDir is the directory
+// of the config file. Both directories must be absolute.
+func (c generatorConfig) findPackage(configDir, dir string) (packageConfig, error) {
+	for _, pkg := range c.Packages {
+		if filepath.Join(configDir, pkg.Dir) == dir {
+			return pkg, nil
+		}
+	}
+
+	return packageConfig{}, fmt.Errorf("package in directory %s not found in config", dir)
+}
+
+// settings returns the settings of the package, where the settings of the package override the top-level settings.
+func (c generatorConfig) settings(pkg packageConfig) generatorSettings {
+	envelope := c.Scheme.Envelope
+	if pkg.Scheme.Envelope != nil {
+		envelope = pkg.Scheme.Envelope
+	}
+
+	return generatorSettings{
+		Scheme: schemeSettings{
+			Namespace: withDefault(pkg.Scheme.Namespace, c.Scheme.Namespace),
+			Protocol:  withDefault(pkg.Scheme.Protocol, c.Scheme.Protocol),
+			DataType:  withDefault(pkg.Scheme.DataType, c.Scheme.DataType),
+			Envelope:  envelope,
+		},
+		BuildTags:     withDefault(pkg.BuildTags, c.BuildTags),
+		KeyStyle:      withDefault(pkg.KeyStyle, c.KeyStyle),
+		KeyPrefix:     withDefault(pkg.KeyPrefix, c.KeyPrefix),
+		TimePrecision: withDefault(pkg.TimePrecision, c.TimePrecision),
+		Avsc:          pkg.Avsc || c.Avsc,
+		JSONSchema:    pkg.JSONSchema || c.JSONSchema,
+		Proto:         pkg.Proto || c.Proto,
+		Docs:          pkg.Docs || c.Docs,
+		DocsFormat:    withDefault(pkg.DocsFormat, c.DocsFormat),
+	}
+}
+
+// typeMarkers returns the settings of the type as markers, which override the markers in the source code.
+func (t typeConfig) typeMarkers() typeMarkers {
+	return typeMarkers{
+		schemeProtocol:  t.Scheme.Protocol,
+		schemeNamespace: t.Scheme.Namespace,
+		schemeDataType:  t.Scheme.DataType,
+	}
+}
+
+// mergeTypeMarkers returns the markers where the non-empty settings of the overrides replace the settings
+// of the markers.
+func mergeTypeMarkers(markers, overrides typeMarkers) typeMarkers {
+	return typeMarkers{
+		schemeProtocol:  withDefault(overrides.schemeProtocol, markers.schemeProtocol),
+		schemeNamespace: withDefault(overrides.schemeNamespace, markers.schemeNamespace),
+		schemeDataType:  withDefault(overrides.schemeDataType, markers.schemeDataType),
+		buildTags:       withDefault(overrides.buildTags, markers.buildTags),
+		generate:        markers.generate || overrides.generate,
+	}
+}
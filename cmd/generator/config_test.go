@@ -0,0 +1,314 @@
+//go:build generator
+
+package main
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestDecodeConfig(t *testing.T) {
+	t.Parallel()
+
+	expectedCfg := generatorConfig{
+		generatorSettings: generatorSettings{
+			Scheme: schemeSettings{
+				Namespace: "gateway.nginx.org",
+				Envelope: &schemeEnvelope{
+					DataTypeAnnotation: "datatype",
+					Fields:             []envelopeField{{Name: "timestamp", Type: "long"}},
+				},
+			},
+			BuildTags:  "generator",
+			KeyStyle:   "snake",
+			Avsc:       true,
+			JSONSchema: true,
+			Docs:       true,
+			DocsFormat: "html",
+		},
+		Packages: []packageConfig{
+			{
+				Dir:    "internal/telemetry",
+				Output: "telemetry_attributes_generated.go",
+				generatorSettings: generatorSettings{
+					KeyPrefix: "ngf.",
+				},
+				Types: []typeConfig{
+					{
+						Name: "Data",
+						Scheme: typeSchemeConfig{
+							schemeSettings: schemeSettings{
+								Protocol: "NGFProductTelemetry",
+								DataType: "ngf-product-telemetry",
+							},
+							Output:           "data.avdl",
+							AvscOutput:       "data.avsc",
+							JSONSchemaOutput: "data.schema.json",
+							ProtoOutput:      "data.proto",
+							DocsOutput:       "data.html",
+						},
+					},
+					{
+						Name: "OtherData",
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		content        string
+		expectedErrMsg string
+		expected       generatorConfig
+	}{
+		{
+			name: "yaml",
+			content: `
+buildTags: generator
+keyStyle: snake
+avsc: true
+jsonSchema: true
+docs: true
+docsFormat: html
+scheme:
+  namespace: gateway.nginx.org
+  envelope:
+    dataTypeAnnotation: datatype
+    fields:
+      - name: timestamp
+        type: long
+packages:
+  - dir: internal/telemetry
+    output: telemetry_attributes_generated.go
+    keyPrefix: ngf.
+    types:
+      - name: Data
+        scheme:
+          protocol: NGFProductTelemetry
+          dataType: ngf-product-telemetry
+          output: data.avdl
+          avscOutput: data.avsc
+          jsonSchemaOutput: data.schema.json
+          protoOutput: data.proto
+          docsOutput: data.html
+      - name: OtherData
+`,
+			expected: expectedCfg,
+		},
+		{
+			name: "json",
+			content: `{
+  "buildTags": "generator",
+  "keyStyle": "snake",
+  "avsc": true,
+  "jsonSchema": true,
+  "docs": true,
+  "docsFormat": "html",
+  "scheme": {
+    "namespace": "gateway.nginx.org",
+    "envelope": {"dataTypeAnnotation": "datatype", "fields": [{"name": "timestamp", "type": "long"}]}
+  },
+  "packages": [
+    {
+      "dir": "internal/telemetry",
+      "output": "telemetry_attributes_generated.go",
+      "keyPrefix": "ngf.",
+      "types": [
+        {
+          "name": "Data",
+          "scheme": {
+            "protocol": "NGFProductTelemetry",
+            "dataType": "ngf-product-telemetry",
+            "output": "data.avdl",
+            "avscOutput": "data.avsc",
+            "jsonSchemaOutput": "data.schema.json",
+            "protoOutput": "data.proto",
+            "docsOutput": "data.html"
+          }
+        },
+        {"name": "OtherData"}
+      ]
+    }
+  ]
+}`,
+			expected: expectedCfg,
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: generatorConfig{},
+		},
+		{
+			name:           "unknown field",
+			content:        "keystyle: snake",
+			expectedErrMsg: "field keystyle not found",
+		},
+		{
+			name:           "missing dir",
+			content:        "packages:\n  - output: generated.go",
+			expectedErrMsg: "invalid config: package 0: dir is required",
+		},
+		{
+			name:           "duplicate dir",
+			content:        "packages:\n  - dir: telemetry\n  - dir: ./telemetry",
+			expectedErrMsg: "invalid config: package ./telemetry: already exists",
+		},
+		{
+			name:           "missing type name",
+			content:        "packages:\n  - dir: telemetry\n    types:\n      - scheme:\n          protocol: Telemetry",
+			expectedErrMsg: "invalid config: package telemetry: type 0: name is required",
+		},
+		{
+			name:           "invalid envelope",
+			content:        "scheme:\n  envelope:\n    dataTypeAnnotation: doc",
+			expectedErrMsg: `invalid config: scheme envelope: invalid annotation "doc"`,
+		},
+		{
+			name:           "invalid envelope of package",
+			content:        "packages:\n  - dir: telemetry\n    scheme:\n      envelope:\n        fields:\n          - name: t",
+			expectedErrMsg: "invalid config: package telemetry: scheme envelope: field t: type must be",
+		},
+		{
+			name: "invalid envelope of type",
+			content: "packages:\n  - dir: telemetry\n    types:\n      - name: Data\n        scheme:\n" +
+				"          envelope:\n            annotations:\n              \"a b\": c",
+			expectedErrMsg: `invalid config: package telemetry: type Data: scheme envelope: invalid annotation "a b"`,
+		},
+		{
+			name:           "duplicate type name",
+			content:        "packages:\n  - dir: telemetry\n    types:\n      - name: Data\n      - name: Data",
+			expectedErrMsg: "invalid config: package telemetry: type Data: already exists",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			cfg, err := decodeConfig(strings.NewReader(test.content))
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(test.expectedErrMsg)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(cfg).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg, err := loadConfig("tests/config.yaml")
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cfg.BuildTags).To(Equal("generator"))
+
+	_, err = loadConfig("tests/notfound.yaml")
+
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read config")))
+}
+
+func TestFindPackage(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := generatorConfig{
+		Packages: []packageConfig{
+			{Dir: "internal/telemetry"},
+			{Dir: "./pkg/telemetry/"},
+		},
+	}
+
+	pkg, err := cfg.findPackage("/project", "/project/pkg/telemetry")
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(pkg).To(Equal(cfg.Packages[1]))
+
+	_, err = cfg.findPackage("/project", "/project/internal")
+
+	g.Expect(err).To(MatchError("package in directory /project/internal not found in config"))
+}
+
+func TestConfigSettings(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := generatorConfig{
+		generatorSettings: generatorSettings{
+			Scheme: schemeSettings{
+				Namespace: "gateway.nginx.org",
+				Protocol:  "Telemetry",
+			},
+			BuildTags: "generator",
+			KeyStyle:  "snake",
+		},
+	}
+
+	envelope := &schemeEnvelope{DataTypeAnnotation: "-"}
+
+	pkg := packageConfig{
+		generatorSettings: generatorSettings{
+			Scheme: schemeSettings{
+				Envelope: envelope,
+				Protocol: "NGFProductTelemetry",
+				DataType: "ngf-product-telemetry",
+			},
+			KeyStyle:      "dotted",
+			TimePrecision: "us",
+		},
+	}
+
+	expected := generatorSettings{
+		Scheme: schemeSettings{
+			Envelope:  envelope,
+			Namespace: "gateway.nginx.org",
+			Protocol:  "NGFProductTelemetry",
+			DataType:  "ngf-product-telemetry",
+		},
+		BuildTags:     "generator",
+		KeyStyle:      "dotted",
+		TimePrecision: "us",
+	}
+
+	g.Expect(cfg.settings(pkg)).To(Equal(expected))
+}
+
+func TestMergeTypeMarkers(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	markers := typeMarkers{
+		schemeProtocol:  "NGFProductTelemetry",
+		schemeNamespace: "gateway.nginx.org",
+		buildTags:       "generator",
+		generate:        true,
+	}
+
+	typeCfg := typeConfig{
+		Name: "Data",
+		Scheme: typeSchemeConfig{
+			schemeSettings: schemeSettings{
+				Protocol: "Telemetry",
+				DataType: "telemetry",
+			},
+		},
+	}
+
+	expected := typeMarkers{
+		schemeProtocol:  "Telemetry",
+		schemeNamespace: "gateway.nginx.org",
+		schemeDataType:  "telemetry",
+		buildTags:       "generator",
+		generate:        true,
+	}
+
+	g.Expect(mergeTypeMarkers(markers, typeCfg.typeMarkers())).To(Equal(expected))
+}
@@ -0,0 +1,146 @@
+//go:build generator
+
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContextLines is the number of unchanged lines around the changes in a unified diff.
+const diffContextLines = 3
+
+// diffOp is an operation of the edit script that turns the old lines into the new lines.
+type diffOp struct {
+	line string
+	// kind is ' ' for an unchanged line, '-' for a removed line and '+' for an added line.
+	kind byte
+}
+
+// unifiedDiff returns the unified diff between the old and the new content, or an empty string if they're equal.
+func unifiedDiff(oldName, newName, oldContent, newContent string) string {
+	if oldContent == newContent {
+		return ""
+	}
+
+	ops := diffLines(splitLines(oldContent), splitLines(newContent))
+
+	var sb strings.Builder
+
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+
+	// oldLines and newLines are the numbers of the old and new lines before each op
+	oldLines := make([]int, len(ops)+1)
+	newLines := make([]int, len(ops)+1)
+
+	for i, op := range ops {
+		oldLines[i+1], newLines[i+1] = oldLines[i], newLines[i]
+
+		if op.kind != '+' {
+			oldLines[i+1]++
+		}
+		if op.kind != '-' {
+			newLines[i+1]++
+		}
+	}
+
+	for start := 0; start < len(ops); {
+		if ops[start].kind == ' ' {
+			start++
+			continue
+		}
+
+		// extend the hunk while the next change is close enough to share the context
+		end := start + 1
+		for next := end; next < len(ops) && next-end < 2*diffContextLines+1; next++ {
+			if ops[next].kind != ' ' {
+				end = next + 1
+			}
+		}
+
+		hunkStart := max(start-diffContextLines, 0)
+		hunkEnd := min(end+diffContextLines, len(ops))
+
+		writeHunkHeader(&sb, oldLines[hunkStart], oldLines[hunkEnd], newLines[hunkStart], newLines[hunkEnd])
+
+		for _, op := range ops[hunkStart:hunkEnd] {
+			sb.WriteByte(op.kind)
+			sb.WriteString(op.line)
+
+			if !strings.HasSuffix(op.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+
+		start = hunkEnd
+	}
+
+	return sb.String()
+}
+
+// writeHunkHeader writes the header of the hunk with the old lines [oldFrom, oldTo) and the new lines [newFrom, newTo),
+// counted from zero.
+func writeHunkHeader(sb *strings.Builder, oldFrom, oldTo, newFrom, newTo int) {
+	// an empty range starts at the line before it, while a non-empty range starts at its first line counted from one
+	oldStart, newStart := oldFrom, newFrom
+	if oldTo > oldFrom {
+		oldStart++
+	}
+	if newTo > newFrom {
+		newStart++
+	}
+
+	fmt.Fprintf(sb, "@@ -%d,%d +%d,%d @@\n", oldStart, oldTo-oldFrom, newStart, newTo-newFrom)
+}
+
+// splitLines splits the content into lines, keeping the line endings.
+func splitLines(content string) []string {
+	lines := strings.SplitAfter(content, "\n")
+
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// diffLines returns the edit script that turns the old lines into the new lines, based on their longest common
+// subsequence.
+func diffLines(oldLines, newLines []string) []diffOp {
+	// lcs[i][j] is the length of the longest common subsequence of oldLines[i:] and newLines[j:]
+	lcs := make([][]int, len(oldLines)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(newLines)+1)
+	}
+
+	for i := len(oldLines) - 1; i >= 0; i-- {
+		for j := len(newLines) - 1; j >= 0; j-- {
+			if oldLines[i] == newLines[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	ops := make([]diffOp, 0, max(len(oldLines), len(newLines)))
+
+	i, j := 0, 0
+
+	for i < len(oldLines) || j < len(newLines) {
+		switch {
+		case i < len(oldLines) && j < len(newLines) && oldLines[i] == newLines[j]:
+			ops = append(ops, diffOp{kind: ' ', line: oldLines[i]})
+			i++
+			j++
+		case j == len(newLines) || (i < len(oldLines) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{kind: '-', line: oldLines[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{kind: '+', line: newLines[j]})
+			j++
+		}
+	}
+
+	return ops
+}
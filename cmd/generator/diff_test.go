@@ -0,0 +1,105 @@
+//go:build generator
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		oldContent string
+		newContent string
+		expected   string
+	}{
+		{
+			name:       "equal",
+			oldContent: "a\nb\n",
+			newContent: "a\nb\n",
+			expected:   "",
+		},
+		{
+			name:       "new file",
+			oldContent: "",
+			newContent: "a\nb\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -0,0 +1,2 @@\n" +
+				"+a\n" +
+				"+b\n",
+		},
+		{
+			name:       "changed line with context",
+			oldContent: "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			newContent: "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -2,7 +2,7 @@\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				"-5\n" +
+				"+five\n" +
+				" 6\n" +
+				" 7\n" +
+				" 8\n",
+		},
+		{
+			name:       "separate hunks",
+			oldContent: "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			newContent: "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n" +
+				"-1\n" +
+				"+one\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				"@@ -9,4 +9,3 @@\n" +
+				" 9\n" +
+				" 10\n" +
+				" 11\n" +
+				"-12\n",
+		},
+		{
+			name:       "merged hunks",
+			oldContent: "1\n2\n3\n4\n5\n6\n7\n8\n",
+			newContent: "one\n2\n3\n4\n5\n6\n7\neight\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,8 +1,8 @@\n" +
+				"-1\n" +
+				"+one\n" +
+				" 2\n" +
+				" 3\n" +
+				" 4\n" +
+				" 5\n" +
+				" 6\n" +
+				" 7\n" +
+				"-8\n" +
+				"+eight\n",
+		},
+		{
+			name:       "no newline at end of file",
+			oldContent: "a\nb",
+			newContent: "a\nb\n",
+			expected: "--- old\n+++ new\n" +
+				"@@ -1,2 +1,2 @@\n" +
+				" a\n" +
+				"-b\n" +
+				"\\ No newline at end of file\n" +
+				"+b\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(unifiedDiff("old", "new", test.oldContent, test.newContent)).To(Equal(test.expected))
+		})
+	}
+}
@@ -0,0 +1,211 @@
+//go:build generator
+
+package main
+
+import (
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// docsFormat is the format of the data dictionary.
+type docsFormat string
+
+const (
+	docsFormatMarkdown docsFormat = "markdown"
+	docsFormatHTML     docsFormat = "html"
+)
+
+// docsFormats are the supported formats of the data dictionary.
+var docsFormats = []docsFormat{
+	docsFormatMarkdown,
+	docsFormatHTML,
+}
+
+// docsFileExtensions are the extensions of the data dictionary files for the formats.
+var docsFileExtensions = map[docsFormat]string{
+	docsFormatMarkdown: ".md",
+	docsFormatHTML:     ".html",
+}
+
+// docsHeader is the header of the data dictionary in both formats.
+const docsHeader = "<!-- This is a generated file. DO NOT EDIT. -->\n"
+
+const docsMarkdownTemplate = docsHeader + `
+# {{ .Record }}
+
+{{ .Description }}
+
+| Field | Attribute | Type | Description | Source |
+| --- | --- | --- | --- | --- |
+{{- range .Fields }}
+| ` + "`{{ .Name }}`" + ` | ` + "`{{ .Key }}`" + ` | {{ .Type }} | {{ markdown .Description }} | {{ .Source }} |
+{{- end }}
+`
+
+// docsHTMLTemplate doesn't include the header, because html/template strips the comments.
+const docsHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Record }}</title>
+</head>
+<body>
+<h1>{{ .Record }}</h1>
+<p>{{ .Description }}</p>
+<table>
+<thead>
+<tr><th>Field</th><th>Attribute</th><th>Type</th><th>Description</th><th>Source</th></tr>
+</thead>
+<tbody>
+{{- range .Fields }}
+<tr>
+<td><code>{{ .Name }}</code></td>
+<td><code>{{ .Key }}</code></td>
+<td>{{ .Type }}</td>
+<td>{{ .Description }}</td>
+<td>{{ .Source }}</td>
+</tr>
+{{- end }}
+</tbody>
+</table>
+</body>
+</html>
+`
+
+type docsGen struct {
+	Record      string
+	Description string
+	Fields      []docsField
+}
+
+// docsField is a field of the data dictionary.
+type docsField struct {
+	// Name is the name of the field in the schemes.
+	Name string
+	// Key is the key of the attribute of the field.
+	Key         string
+	Type        string
+	Description string
+	// Source is the struct that declares the field, which is an embedded struct for the fields of embedded structs.
+	Source string
+}
+
+type docsGenConfig struct {
+	format docsFormat
+	record string
+	fields []field
+	// envelope is the envelope of the record, whose fields are mentioned in the description.
+	envelope schemeEnvelope
+}
+
+// getDocsType returns the type of the field in the data dictionary, which doesn't depend on the scheme format.
+func getDocsType(f field) string {
+	valueType := getJSONSchemaPrimitiveType(f.fieldType)
+
+	switch {
+	case f.slice:
+		return "array of " + valueType
+	case f.stringMap:
+		return "map of " + valueType
+	default:
+		return valueType
+	}
+}
+
+// getDocsDescription returns the description of the field with the details of its values: the values of an enum and
+// the units of a time value.
+func getDocsDescription(f field) string {
+	details := []string{f.docString}
+
+	if isAvroEnum(f) {
+		details = append(details, fmt.Sprintf("One of: %s.", strings.Join(f.named.enumValues, ", ")))
+	}
+
+	switch f.timeKind {
+	case timeKindTimestamp:
+		details = append(details, fmt.Sprintf("Unix time in %s.", timeUnits[f.timePrecision]))
+	case timeKindDuration:
+		details = append(details, fmt.Sprintf("Duration in %s.", timeUnits[f.timePrecision]))
+	case timeKindNone:
+	}
+
+	return strings.TrimSpace(strings.Join(details, " "))
+}
+
+// getDocsFields returns the fields of the data dictionary, including the fields of the embedded structs, whose source
+// is the embedded struct.
+func getDocsFields(source string, fields []field) []docsField {
+	var result []docsField
+
+	for _, f := range fields {
+		if f.embeddedStruct {
+			result = append(result, getDocsFields(f.embeddedStructName(), f.embeddedStructFields)...)
+
+			continue
+		}
+
+		result = append(result, docsField{
+			Name:        getAvroName(f.attributeKey()),
+			Key:         f.attributeKey(),
+			Type:        getDocsType(f),
+			Description: getDocsDescription(f),
+			Source:      source,
+		})
+	}
+
+	return result
+}
+
+// formatMarkdownCell escapes the text for a cell of a Markdown table, which can't contain pipes or new lines.
+func formatMarkdownCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", "<br>").Replace(text)
+}
+
+// generateDocs generates the data dictionary of the record, which lists the fields collected with their types and
+// descriptions.
+func generateDocs(writer io.Writer, cfg docsGenConfig) error {
+	description := cfg.record + " is the telemetry data for the product."
+
+	if envelopeFields := cfg.envelope.getFields(); len(envelopeFields) > 0 {
+		noun := "fields"
+		if len(envelopeFields) == 1 {
+			noun = "field"
+		}
+
+		description += fmt.Sprintf(" Besides the fields below, every record includes the %s %s.",
+			getEnvelopeFieldNames(envelopeFields), noun)
+	}
+
+	dg := docsGen{
+		Record:      cfg.record,
+		Description: description,
+		Fields:      getDocsFields(cfg.record, cfg.fields),
+	}
+
+	var err error
+
+	switch cfg.format {
+	case docsFormatMarkdown:
+		funcMap := template.FuncMap{"markdown": formatMarkdownCell}
+		tmpl := template.Must(template.New("docs").Funcs(funcMap).Parse(docsMarkdownTemplate))
+		err = tmpl.Execute(writer, dg)
+	case docsFormatHTML:
+		if _, err := io.WriteString(writer, docsHeader); err != nil {
+			return fmt.Errorf("failed to write header: %w", err)
+		}
+
+		tmpl := htmltemplate.Must(htmltemplate.New("docs").Parse(docsHTMLTemplate))
+		err = tmpl.Execute(writer, dg)
+	default:
+		return fmt.Errorf("unsupported docs format %q", cfg.format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to execute template: %w", err)
+	}
+
+	return nil
+}
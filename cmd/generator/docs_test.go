@@ -0,0 +1,201 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"go/types"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGenerateDocs(t *testing.T) {
+	t.Parallel()
+
+	fields := []field{
+		{docString: "Name is a name | title.\nMore comments.", name: "Name", fieldType: types.String},
+		{name: "Counts", key: "counts", fieldType: types.Int32, slice: true},
+		{
+			docString:     "Started is the start time.",
+			name:          "Started",
+			fieldType:     types.Int64,
+			timeKind:      timeKindTimestamp,
+			timePrecision: timePrecisionMilliseconds,
+		},
+		{
+			name:                  "ClusterData",
+			embeddedStruct:        true,
+			embeddedStructPackage: "github.com/nginx/telemetry-exporter/cluster",
+			embeddedStructFields: []field{
+				{docString: "Nodes is the number of nodes.", name: "Nodes", fieldType: types.Int, pointer: true},
+			},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		format   docsFormat
+		expected string
+	}{
+		{
+			name:   "markdown",
+			format: docsFormatMarkdown,
+			expected: `<!-- This is a generated file. DO NOT EDIT. -->
+
+# Data
+
+Data is the telemetry data for the product. Besides the fields below, every record includes the dataType, ` +
+				`eventTime and ingestTime fields.
+
+| Field | Attribute | Type | Description | Source |
+| --- | --- | --- | --- | --- |
+| ` + "`Name`" + ` | ` + "`Name`" + ` | string | Name is a name \| title.<br>More comments. | Data |
+| ` + "`counts`" + ` | ` + "`counts`" + ` | array of integer |  | Data |
+| ` + "`Started`" + ` | ` + "`Started`" + ` | integer | Started is the start time. Unix time in milliseconds. | Data |
+| ` + "`Nodes`" + ` | ` + "`Nodes`" + ` | integer | Nodes is the number of nodes. | cluster.ClusterData |
+`,
+		},
+		{
+			name:   "html",
+			format: docsFormatHTML,
+			expected: `<!-- This is a generated file. DO NOT EDIT. -->
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Data</title>
+</head>
+<body>
+<h1>Data</h1>
+<p>Data is the telemetry data for the product. Besides the fields below, every record includes the dataType, ` +
+				`eventTime and ingestTime fields.</p>
+<table>
+<thead>
+<tr><th>Field</th><th>Attribute</th><th>Type</th><th>Description</th><th>Source</th></tr>
+</thead>
+<tbody>
+<tr>
+<td><code>Name</code></td>
+<td><code>Name</code></td>
+<td>string</td>
+<td>Name is a name | title.
+More comments.</td>
+<td>Data</td>
+</tr>
+<tr>
+<td><code>counts</code></td>
+<td><code>counts</code></td>
+<td>array of integer</td>
+<td></td>
+<td>Data</td>
+</tr>
+<tr>
+<td><code>Started</code></td>
+<td><code>Started</code></td>
+<td>integer</td>
+<td>Started is the start time. Unix time in milliseconds.</td>
+<td>Data</td>
+</tr>
+<tr>
+<td><code>Nodes</code></td>
+<td><code>Nodes</code></td>
+<td>integer</td>
+<td>Nodes is the number of nodes.</td>
+<td>cluster.ClusterData</td>
+</tr>
+</tbody>
+</table>
+</body>
+</html>
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var buf bytes.Buffer
+
+			cfg := docsGenConfig{
+				format: test.format,
+				record: "Data",
+				fields: fields,
+			}
+
+			g.Expect(generateDocs(&buf, cfg)).To(Succeed())
+			g.Expect(buf.String()).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestGenerateDocsEscapesHTML(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := docsGenConfig{
+		format: docsFormatHTML,
+		record: "Data",
+		fields: []field{
+			{docString: "Name is a <b>name</b> & title.", name: "Name", fieldType: types.String},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateDocs(&buf, cfg)).To(Succeed())
+	g.Expect(buf.String()).To(ContainSubstring("<td>Name is a &lt;b&gt;name&lt;/b&gt; &amp; title.</td>"))
+}
+
+func TestGenerateDocsEnvelope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected string
+		fields   []envelopeField
+	}{
+		{
+			name:   "single field",
+			fields: []envelopeField{{Name: "timestamp", Type: "long"}},
+			expected: "Data is the telemetry data for the product. Besides the fields below, every record includes the " +
+				"timestamp field.",
+		},
+		{
+			name:     "no fields",
+			fields:   []envelopeField{},
+			expected: "Data is the telemetry data for the product.",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			cfg := docsGenConfig{
+				format:   docsFormatMarkdown,
+				record:   "Data",
+				envelope: schemeEnvelope{Fields: test.fields},
+			}
+
+			var buf bytes.Buffer
+
+			g.Expect(generateDocs(&buf, cfg)).To(Succeed())
+			g.Expect(buf.String()).To(ContainSubstring("\n" + test.expected + "\n"))
+		})
+	}
+}
+
+func TestGenerateDocsUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var buf bytes.Buffer
+
+	err := generateDocs(&buf, docsGenConfig{format: "pdf", record: "Data"})
+
+	g.Expect(err).To(MatchError(`unsupported docs format "pdf"`))
+}
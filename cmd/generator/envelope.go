@@ -0,0 +1,272 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// schemeEnvelope is the envelope of the records of the schemes: the fields and the annotations of the records that
+// are set by the ingestion backend rather than by the telemetry data. The default envelope is the envelope of the
+// data fabric. For example, in YAML:
+//
+//	dataTypeAnnotation: datatype
+//	annotations:
+//	  retention: 90d
+//	fields:
+//	  - name: dataType
+//	    type: string
+//	    doc: The type of the data.
+//	    dataType: true
+//	  - name: timestamp
+//	    type: long
+type schemeEnvelope struct {
+	// Annotations are the additional annotations of the records, which are properties of the records in Avro JSON
+	// schema.
+	Annotations map[string]string `yaml:"annotations"`
+	// DataTypeAnnotation is the annotation of the records that is set to the data type. The default is df_datatype.
+	// "-" removes the annotation.
+	DataTypeAnnotation string `yaml:"dataTypeAnnotation"`
+	// Fields are the fields of the envelope, which precede the fields of the data. Nil means the default fields,
+	// while an empty list removes them.
+	Fields []envelopeField `yaml:"fields"`
+}
+
+// envelopeField is a field of the envelope.
+type envelopeField struct {
+	// Name is the Avro name of the field.
+	Name string `yaml:"name"`
+	// Type is the Avro primitive type of the field.
+	Type string `yaml:"type"`
+	// Doc is the description of the field.
+	Doc string `yaml:"doc"`
+	// DataType marks the string field that the generated code sets to the data type. At most one field can be
+	// marked.
+	DataType bool `yaml:"dataType"`
+}
+
+const (
+	// defaultDataTypeAnnotation is the annotation of the data type of the data fabric.
+	defaultDataTypeAnnotation = "df_datatype"
+	// noDataTypeAnnotation removes the data type annotation.
+	noDataTypeAnnotation = "-"
+)
+
+// defaultEnvelopeFields are the fields added by the data fabric.
+var defaultEnvelopeFields = []envelopeField{
+	{Name: "dataType", Type: "string", Doc: "The field that identifies what type of data this is.", DataType: true},
+	{Name: "eventTime", Type: "long", Doc: "The time the event occurred"},
+	{Name: "ingestTime", Type: "long", Doc: "The time our edge ingested the event"},
+}
+
+// avroAnnotationRegexp matches the names of the annotations supported by the generator.
+var avroAnnotationRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// avroRecordAttributes are the attributes of the records in Avro JSON schema, which can't be used as annotations.
+var avroRecordAttributes = []string{"type", "name", "namespace", "doc", "aliases", "fields"}
+
+// schemeAnnotation is an annotation of a record.
+type schemeAnnotation struct {
+	Name  string
+	Value string
+}
+
+// getFields returns the fields of the envelope.
+func (e schemeEnvelope) getFields() []envelopeField {
+	if e.Fields == nil {
+		return defaultEnvelopeFields
+	}
+
+	return e.Fields
+}
+
+// getDataTypeField returns the name of the field of the data type, or an empty string if there is none.
+func (e schemeEnvelope) getDataTypeField() string {
+	for _, f := range e.getFields() {
+		if f.DataType {
+			return f.Name
+		}
+	}
+
+	return ""
+}
+
+// getReservedKeys returns the attribute keys that the fields can't use, because they are the names of the fields
+// of the envelope, including the field of the data type that is set by the generated code.
+func (e schemeEnvelope) getReservedKeys() map[string]struct{} {
+	keys := make(map[string]struct{}, len(e.getFields()))
+	for _, f := range e.getFields() {
+		keys[f.Name] = struct{}{}
+	}
+
+	return keys
+}
+
+// getDataTypeAnnotation returns the annotation of the data type, or an empty string if it is removed.
+func (e schemeEnvelope) getDataTypeAnnotation() string {
+	switch e.DataTypeAnnotation {
+	case "":
+		return defaultDataTypeAnnotation
+	case noDataTypeAnnotation:
+		return ""
+	default:
+		return e.DataTypeAnnotation
+	}
+}
+
+// getAnnotations returns the annotations of the record of the data type: the data type annotation followed by
+// the additional annotations sorted by name.
+func (e schemeEnvelope) getAnnotations(dataType string) []schemeAnnotation {
+	annotations := make([]schemeAnnotation, 0, len(e.Annotations)+1)
+
+	if name := e.getDataTypeAnnotation(); name != "" {
+		annotations = append(annotations, schemeAnnotation{Name: name, Value: dataType})
+	}
+
+	names := make([]string, 0, len(e.Annotations))
+	for name := range e.Annotations {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	for _, name := range names {
+		annotations = append(annotations, schemeAnnotation{Name: name, Value: e.Annotations[name]})
+	}
+
+	return annotations
+}
+
+// getEnvelopeFieldNames returns the names of the fields of the envelope, like "a, b and c".
+func getEnvelopeFieldNames(fields []envelopeField) string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// validate validates that the fields of the envelope are unique Avro primitive fields and that the annotations
+// are valid.
+func (e schemeEnvelope) validate() error {
+	names := make(map[string]struct{}, len(e.getFields()))
+	dataTypeField := ""
+
+	for i, f := range e.getFields() {
+		if !avroNameRegexp.MatchString(f.Name) {
+			return fmt.Errorf("field %d: invalid name %q", i, f.Name)
+		}
+
+		if _, exists := names[f.Name]; exists {
+			return fmt.Errorf("field %s: already exists", f.Name)
+		}
+		names[f.Name] = struct{}{}
+
+		if _, primitive := avroPrimitiveTypes[f.Type]; !primitive || f.Type == "null" {
+			return fmt.Errorf("field %s: type must be an Avro primitive type other than null, got %q", f.Name, f.Type)
+		}
+
+		if !f.DataType {
+			continue
+		}
+
+		if f.Type != "string" {
+			return fmt.Errorf("field %s: data type field must be a string, got %q", f.Name, f.Type)
+		}
+
+		if dataTypeField != "" {
+			return fmt.Errorf("field %s: data type field is already %s", f.Name, dataTypeField)
+		}
+
+		dataTypeField = f.Name
+	}
+
+	annotations := make([]string, 0, len(e.Annotations)+1)
+	for name := range e.Annotations {
+		annotations = append(annotations, name)
+	}
+
+	if name := e.getDataTypeAnnotation(); name != "" {
+		if _, exists := e.Annotations[name]; exists {
+			return fmt.Errorf("annotation %s: already used for the data type", name)
+		}
+
+		annotations = append(annotations, name)
+	}
+
+	for _, name := range annotations {
+		if !avroAnnotationRegexp.MatchString(name) || slices.Contains(avroRecordAttributes, name) {
+			return fmt.Errorf("invalid annotation %q", name)
+		}
+	}
+
+	return nil
+}
+
+// checkEnvelopeFields checks that the names of the fields of the envelope are not used by the fields of the record.
+func checkEnvelopeFields(envelope schemeEnvelope, fields []field) error {
+	var check func([]field) error
+	check = func(fields []field) error {
+		for _, f := range fields {
+			if f.embeddedStruct {
+				if err := check(f.embeddedStructFields); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			name := getAvroName(f.attributeKey())
+
+			if slices.ContainsFunc(envelope.getFields(), func(e envelopeField) bool { return e.Name == name }) {
+				return fmt.Errorf("field %s: Avro name %s is used by the envelope", f.name, name)
+			}
+		}
+
+		return nil
+	}
+
+	return check(fields)
+}
+
+// loadSchemeEnvelope loads the envelope from a YAML or JSON file. Unknown fields are rejected.
+func loadSchemeEnvelope(path string) (schemeEnvelope, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // the path of the envelope is set by the user
+	if err != nil {
+		return schemeEnvelope{}, fmt.Errorf("failed to read scheme envelope: %w", err)
+	}
+
+	return decodeSchemeEnvelope(bytes.NewReader(content))
+}
+
+// decodeSchemeEnvelope decodes the envelope from YAML or JSON, which is a subset of YAML.
+func decodeSchemeEnvelope(reader io.Reader) (schemeEnvelope, error) {
+	decoder := yaml.NewDecoder(reader)
+	decoder.KnownFields(true)
+
+	var envelope schemeEnvelope
+
+	if err := decoder.Decode(&envelope); err != nil && !errors.Is(err, io.EOF) {
+		return schemeEnvelope{}, fmt.Errorf("failed to decode scheme envelope: %w", err)
+	}
+
+	if err := envelope.validate(); err != nil {
+		return schemeEnvelope{}, fmt.Errorf("invalid scheme envelope: %w", err)
+	}
+
+	return envelope, nil
+}
@@ -0,0 +1,276 @@
+//go:build generator
+
+package main
+
+import (
+	"go/types"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSchemeEnvelopeGetAnnotations(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		envelope schemeEnvelope
+		expected []schemeAnnotation
+	}{
+		{
+			name:     "default",
+			expected: []schemeAnnotation{{Name: "df_datatype", Value: "ngf-product-telemetry"}},
+		},
+		{
+			name: "custom",
+			envelope: schemeEnvelope{
+				DataTypeAnnotation: "datatype",
+				Annotations:        map[string]string{"retention": "90d", "owner": "nginx"},
+			},
+			expected: []schemeAnnotation{
+				{Name: "datatype", Value: "ngf-product-telemetry"},
+				{Name: "owner", Value: "nginx"},
+				{Name: "retention", Value: "90d"},
+			},
+		},
+		{
+			name:     "removed data type annotation",
+			envelope: schemeEnvelope{DataTypeAnnotation: "-"},
+			expected: []schemeAnnotation{},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(test.envelope.getAnnotations("ngf-product-telemetry")).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestSchemeEnvelopeGetFields(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(schemeEnvelope{}.getFields()).To(Equal(defaultEnvelopeFields))
+	g.Expect(schemeEnvelope{Fields: []envelopeField{}}.getFields()).To(BeEmpty())
+}
+
+func TestSchemeEnvelopeGetDataTypeField(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(schemeEnvelope{}.getDataTypeField()).To(Equal("dataType"))
+	g.Expect(schemeEnvelope{Fields: []envelopeField{{Name: "timestamp", Type: "long"}}}.getDataTypeField()).To(BeEmpty())
+
+	custom := schemeEnvelope{
+		Fields: []envelopeField{
+			{Name: "timestamp", Type: "long"},
+			{Name: "kind", Type: "string", DataType: true},
+		},
+	}
+
+	g.Expect(custom.getDataTypeField()).To(Equal("kind"))
+}
+
+func TestSchemeEnvelopeGetReservedKeys(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(schemeEnvelope{}.getReservedKeys()).To(Equal(map[string]struct{}{
+		"dataType":   {},
+		"eventTime":  {},
+		"ingestTime": {},
+	}))
+	g.Expect(schemeEnvelope{Fields: []envelopeField{{Name: "timestamp", Type: "long"}}}.getReservedKeys()).To(Equal(
+		map[string]struct{}{"timestamp": {}},
+	))
+	g.Expect(schemeEnvelope{Fields: []envelopeField{}}.getReservedKeys()).To(BeEmpty())
+}
+
+func TestGetEnvelopeFieldNames(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(getEnvelopeFieldNames(nil)).To(BeEmpty())
+	g.Expect(getEnvelopeFieldNames([]envelopeField{{Name: "a"}})).To(Equal("a"))
+	g.Expect(getEnvelopeFieldNames([]envelopeField{{Name: "a"}, {Name: "b"}})).To(Equal("a and b"))
+	g.Expect(getEnvelopeFieldNames(defaultEnvelopeFields)).To(Equal("dataType, eventTime and ingestTime"))
+}
+
+func TestDecodeSchemeEnvelope(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		content        string
+		expectedErrMsg string
+		expected       schemeEnvelope
+	}{
+		{
+			name: "yaml",
+			content: `
+dataTypeAnnotation: datatype
+annotations:
+  retention: 90d
+fields:
+  - name: dataType
+    type: string
+    doc: The type of the data.
+    dataType: true
+  - name: timestamp
+    type: long
+`,
+			expected: schemeEnvelope{
+				DataTypeAnnotation: "datatype",
+				Annotations:        map[string]string{"retention": "90d"},
+				Fields: []envelopeField{
+					{Name: "dataType", Type: "string", Doc: "The type of the data.", DataType: true},
+					{Name: "timestamp", Type: "long"},
+				},
+			},
+		},
+		{
+			name:     "json without fields",
+			content:  `{"dataTypeAnnotation": "-", "fields": []}`,
+			expected: schemeEnvelope{DataTypeAnnotation: "-", Fields: []envelopeField{}},
+		},
+		{
+			name:     "empty",
+			content:  "",
+			expected: schemeEnvelope{},
+		},
+		{
+			name:           "unknown field",
+			content:        "annotation: datatype",
+			expectedErrMsg: "field annotation not found",
+		},
+		{
+			name:           "invalid field name",
+			content:        "fields:\n  - name: event-time\n    type: long",
+			expectedErrMsg: `invalid scheme envelope: field 0: invalid name "event-time"`,
+		},
+		{
+			name:           "duplicate field",
+			content:        "fields:\n  - name: time\n    type: long\n  - name: time\n    type: long",
+			expectedErrMsg: "invalid scheme envelope: field time: already exists",
+		},
+		{
+			name:           "unsupported field type",
+			content:        "fields:\n  - name: time\n    type: timestamp_ms",
+			expectedErrMsg: `field time: type must be an Avro primitive type other than null, got "timestamp_ms"`,
+		},
+		{
+			name:           "null field type",
+			content:        "fields:\n  - name: time\n    type: \"null\"",
+			expectedErrMsg: `field time: type must be an Avro primitive type other than null, got "null"`,
+		},
+		{
+			name:           "data type field of another type",
+			content:        "fields:\n  - name: kind\n    type: int\n    dataType: true",
+			expectedErrMsg: `invalid scheme envelope: field kind: data type field must be a string, got "int"`,
+		},
+		{
+			name: "multiple data type fields",
+			content: "fields:\n  - name: kind\n    type: string\n    dataType: true\n" +
+				"  - name: type\n    type: string\n    dataType: true",
+			expectedErrMsg: "invalid scheme envelope: field type: data type field is already kind",
+		},
+		{
+			name:           "annotation of the data type",
+			content:        "annotations:\n  df_datatype: data",
+			expectedErrMsg: "invalid scheme envelope: annotation df_datatype: already used for the data type",
+		},
+		{
+			name:           "invalid annotation",
+			content:        "annotations:\n  retention days: 90",
+			expectedErrMsg: `invalid scheme envelope: invalid annotation "retention days"`,
+		},
+		{
+			name:           "record attribute",
+			content:        "dataTypeAnnotation: namespace",
+			expectedErrMsg: `invalid scheme envelope: invalid annotation "namespace"`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			envelope, err := decodeSchemeEnvelope(strings.NewReader(test.content))
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(test.expectedErrMsg)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(envelope).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestLoadSchemeEnvelope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	_, err := loadSchemeEnvelope("tests/notfound.yaml")
+
+	g.Expect(err).To(MatchError(ContainSubstring("failed to read scheme envelope")))
+}
+
+func TestCheckEnvelopeFields(t *testing.T) {
+	t.Parallel()
+
+	fields := []field{
+		{name: "Time", fieldType: types.Int64},
+		{
+			name:                  "ClusterData",
+			embeddedStruct:        true,
+			embeddedStructPackage: "example.com/cluster",
+			embeddedStructFields: []field{
+				{name: "ClusterID", key: "cluster.id", fieldType: types.String},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		expectedErrMsg string
+		envelope       schemeEnvelope
+	}{
+		{
+			name: "default envelope",
+		},
+		{
+			name:           "field",
+			envelope:       schemeEnvelope{Fields: []envelopeField{{Name: "Time", Type: "long"}}},
+			expectedErrMsg: "field Time: Avro name Time is used by the envelope",
+		},
+		{
+			name:           "field of embedded struct",
+			envelope:       schemeEnvelope{Fields: []envelopeField{{Name: "cluster_id", Type: "string"}}},
+			expectedErrMsg: "field ClusterID: Avro name cluster_id is used by the envelope",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			err := checkEnvelopeFields(test.envelope, fields)
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(test.expectedErrMsg))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,221 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/types"
+	"io"
+)
+
+// jsonSchemaDraft is the JSON Schema dialect of the generated schema.
+const jsonSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchema is a JSON Schema. Only the keywords used by the generator are supported.
+type jsonSchema struct {
+	Schema               string               `json:"$schema,omitempty"`
+	Title                string               `json:"title,omitempty"`
+	Description          string               `json:"description,omitempty"`
+	Comment              string               `json:"$comment,omitempty"`
+	Type                 any                  `json:"type,omitempty"`
+	Const                any                  `json:"const,omitempty"`
+	Enum                 []any                `json:"enum,omitempty"`
+	Items                *jsonSchema          `json:"items,omitempty"`
+	AdditionalProperties any                  `json:"additionalProperties,omitempty"`
+	Properties           jsonSchemaProperties `json:"properties,omitempty"`
+	Required             []string             `json:"required,omitempty"`
+}
+
+// jsonSchemaProperty is a property of an object schema.
+type jsonSchemaProperty struct {
+	schema *jsonSchema
+	name   string
+}
+
+// jsonSchemaProperties are the properties of an object schema, marshaled in their order.
+type jsonSchemaProperties []jsonSchemaProperty
+
+func (p jsonSchemaProperties) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.WriteByte('{')
+
+	for i, property := range p {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		name, err := json.Marshal(property.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal name of property %s: %w", property.name, err)
+		}
+
+		schema, err := json.Marshal(property.schema)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal schema of property %s: %w", property.name, err)
+		}
+
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(schema)
+	}
+
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+const jsonTypeNull = "null"
+
+func getJSONSchemaPrimitiveType(kind types.BasicKind) string {
+	switch kind {
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.Uint8, types.Uint16, types.Uint32:
+		return "integer"
+	case types.Float32, types.Float64:
+		return "number"
+	case types.String:
+		return "string"
+	case types.Bool:
+		return "boolean"
+	default:
+		panic(fmt.Sprintf("unexpected kind %v", kind))
+	}
+}
+
+// timeUnits are the units of the time values in the time precisions.
+var timeUnits = map[timePrecision]string{
+	timePrecisionSeconds:      "seconds",
+	timePrecisionMilliseconds: "milliseconds",
+	timePrecisionMicroseconds: "microseconds",
+	timePrecisionNanoseconds:  "nanoseconds",
+}
+
+// getJSONSchemaValueSchema returns the schema of the field value, which is not nullable.
+// Like in the Avro scheme, enums are restricted to their values and time values are integers in the precision
+// of the field.
+func getJSONSchemaValueSchema(f field) *jsonSchema {
+	schema := &jsonSchema{Type: getJSONSchemaPrimitiveType(f.fieldType)}
+
+	if isAvroEnum(f) {
+		schema.Enum = make([]any, 0, len(f.named.enumValues))
+		for _, v := range f.named.enumValues {
+			schema.Enum = append(schema.Enum, v)
+		}
+	}
+
+	switch f.timeKind {
+	case timeKindTimestamp:
+		schema.Comment = "Unix time in " + timeUnits[f.timePrecision]
+	case timeKindDuration:
+		schema.Comment = "Duration in " + timeUnits[f.timePrecision]
+	case timeKindNone:
+	}
+
+	return schema
+}
+
+// getJSONSchemaFieldSchema returns the nullable schema of the field, like the type of the field in the Avro scheme.
+func getJSONSchemaFieldSchema(f field) *jsonSchema {
+	var (
+		schema   *jsonSchema
+		jsonType string
+	)
+
+	switch {
+	case f.slice:
+		schema = &jsonSchema{Items: getJSONSchemaValueSchema(f)}
+		jsonType = "array"
+	case f.stringMap:
+		schema = &jsonSchema{AdditionalProperties: getJSONSchemaValueSchema(f)}
+		jsonType = "object"
+	default:
+		schema = getJSONSchemaValueSchema(f)
+		jsonType = getJSONSchemaPrimitiveType(f.fieldType)
+
+		if schema.Enum != nil {
+			schema.Enum = append(schema.Enum, nil)
+		}
+	}
+
+	schema.Type = []string{jsonType, jsonTypeNull}
+	schema.Description = f.docString
+
+	return schema
+}
+
+type jsonSchemaGenConfig struct {
+	dataFabricDataType string
+	record             string
+	fields             []field
+	// envelope is the envelope of the record, whose fields are required.
+	envelope schemeEnvelope
+}
+
+// jsonSchemaAvroTypes are the JSON types of the Avro primitive types of the envelope fields.
+var jsonSchemaAvroTypes = map[string]string{
+	"boolean": "boolean",
+	"int":     "integer",
+	"long":    "integer",
+	"float":   "number",
+	"double":  "number",
+	"bytes":   "string",
+	"string":  "string",
+}
+
+// generateJSONSchema generates the JSON Schema of the record described by the Avro scheme of the fields,
+// including the fields added by the data fabric, so that the records can be validated with JSON Schema.
+func generateJSONSchema(writer io.Writer, cfg jsonSchemaGenConfig) error {
+	envelopeFields := cfg.envelope.getFields()
+
+	properties := make(jsonSchemaProperties, 0, len(envelopeFields))
+	required := make([]string, 0, len(envelopeFields))
+
+	for _, f := range envelopeFields {
+		schema := &jsonSchema{Description: f.Doc, Type: jsonSchemaAvroTypes[f.Type]}
+		if f.DataType && cfg.dataFabricDataType != "" {
+			schema.Const = cfg.dataFabricDataType
+		}
+
+		properties = append(properties, jsonSchemaProperty{name: f.Name, schema: schema})
+		required = append(required, f.Name)
+	}
+
+	var addProperties func([]field)
+	addProperties = func(fields []field) {
+		for _, f := range fields {
+			if f.embeddedStruct {
+				addProperties(f.embeddedStructFields)
+				continue
+			}
+
+			properties = append(properties, jsonSchemaProperty{
+				name:   getAvroName(f.attributeKey()),
+				schema: getJSONSchemaFieldSchema(f),
+			})
+		}
+	}
+
+	addProperties(cfg.fields)
+
+	schema := jsonSchema{
+		Schema:               jsonSchemaDraft,
+		Title:                cfg.record,
+		Description:          cfg.record + " is the telemetry data for the product.",
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: false,
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(schema); err != nil {
+		return fmt.Errorf("failed to encode JSON schema: %w", err)
+	}
+
+	return nil
+}
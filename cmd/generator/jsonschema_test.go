@@ -0,0 +1,213 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/types"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/nginx/telemetry-exporter/cmd/generator/tests"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	parseCfg := parsingConfig{
+		pkgName:     "tests",
+		typeNames:   []string{"Data"},
+		loadPattern: "github.com/nginx/telemetry-exporter/cmd/generator/tests",
+		buildFlags:  []string{"-tags=generator"},
+	}
+
+	_ = tests.Data{} // depends on the type being defined
+
+	pResult, err := parse(parseCfg)
+
+	g.Expect(err).ToNot(HaveOccurred())
+
+	var buf bytes.Buffer
+
+	jsonSchemaCfg := jsonSchemaGenConfig{
+		dataFabricDataType: "ngf-product-telemetry",
+		record:             "Data",
+		fields:             pResult.types[0].fields,
+	}
+
+	g.Expect(generateJSONSchema(&buf, jsonSchemaCfg)).To(Succeed())
+
+	var schema struct {
+		Properties           map[string]map[string]any `json:"properties"`
+		Title                string                    `json:"title"`
+		Required             []string                  `json:"required"`
+		AdditionalProperties bool                      `json:"additionalProperties"`
+	}
+
+	g.Expect(json.Unmarshal(buf.Bytes(), &schema)).To(Succeed())
+
+	g.Expect(schema.Title).To(Equal("Data"))
+	g.Expect(schema.Required).To(Equal([]string{"dataType", "eventTime", "ingestTime"}))
+	g.Expect(schema.AdditionalProperties).To(BeFalse())
+
+	g.Expect(schema.Properties).To(HaveKeyWithValue("dataType", HaveKeyWithValue("const", "ngf-product-telemetry")))
+	g.Expect(schema.Properties).To(HaveKeyWithValue("SomeString", Equal(map[string]any{
+		"description": "SomeString is a string field.",
+		"type":        []any{"string", "null"},
+	})))
+	g.Expect(schema.Properties).To(HaveKeyWithValue("SomeInt32s", Equal(map[string]any{
+		"description": "SomeInt32s is a slice of int32.",
+		"type":        []any{"array", "null"},
+		"items":       map[string]any{"type": "integer"},
+	})))
+	// the fields of the embedded struct are flattened and the keys are the Avro names
+	g.Expect(schema.Properties).To(HaveKey("AnotherSomeString"))
+	g.Expect(schema.Properties).To(HaveKey("some_keyed_string"))
+	g.Expect(schema.Properties).ToNot(HaveKey("SomeSkippedChannel"))
+
+	// the properties are in the order of the fields
+	g.Expect(buf.String()).To(MatchRegexp(`(?s)"ingestTime".*"SomeString".*"SomeInt".*"AnotherSomeBools"`))
+}
+
+func TestGenerateJSONSchemaEnvelope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	jsonSchemaCfg := jsonSchemaGenConfig{
+		dataFabricDataType: "ngf-product-telemetry",
+		record:             "Data",
+		envelope: schemeEnvelope{
+			Fields: []envelopeField{
+				{Name: "kind", Type: "string", DataType: true},
+				{Name: "dataType", Type: "string"},
+				{Name: "timestamp", Type: "long", Doc: "The time of the record."},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateJSONSchema(&buf, jsonSchemaCfg)).To(Succeed())
+
+	var schema struct {
+		Properties map[string]map[string]any `json:"properties"`
+		Required   []string                  `json:"required"`
+	}
+
+	g.Expect(json.Unmarshal(buf.Bytes(), &schema)).To(Succeed())
+
+	g.Expect(schema.Required).To(Equal([]string{"kind", "dataType", "timestamp"}))
+
+	// only the data type field is set to the data type
+	g.Expect(schema.Properties).To(Equal(map[string]map[string]any{
+		"kind":      {"type": "string", "const": "ngf-product-telemetry"},
+		"dataType":  {"type": "string"},
+		"timestamp": {"type": "integer", "description": "The time of the record."},
+	}))
+}
+
+func TestGetJSONSchemaFieldSchema(t *testing.T) {
+	t.Parallel()
+
+	platform := &namedType{
+		packagePath: testsPackagePath,
+		name:        "Platform",
+		enumValues:  []string{"aws", "gcp"},
+	}
+
+	tests := []struct {
+		name     string
+		expected *jsonSchema
+		field    field
+	}{
+		{
+			name:  "basic",
+			field: field{docString: "Count is a count.", fieldType: types.Int32},
+			expected: &jsonSchema{
+				Description: "Count is a count.",
+				Type:        []string{"integer", "null"},
+			},
+		},
+		{
+			name:  "slice",
+			field: field{docString: "Floats are floats.", fieldType: types.Float32, slice: true},
+			expected: &jsonSchema{
+				Description: "Floats are floats.",
+				Type:        []string{"array", "null"},
+				Items:       &jsonSchema{Type: "number"},
+			},
+		},
+		{
+			name:  "map",
+			field: field{docString: "Flags are flags.", fieldType: types.Bool, stringMap: true},
+			expected: &jsonSchema{
+				Description:          "Flags are flags.",
+				Type:                 []string{"object", "null"},
+				AdditionalProperties: &jsonSchema{Type: "boolean"},
+			},
+		},
+		{
+			name:  "enum",
+			field: field{docString: "Platform is a platform.", fieldType: types.String, named: platform},
+			expected: &jsonSchema{
+				Description: "Platform is a platform.",
+				Type:        []string{"string", "null"},
+				Enum:        []any{"aws", "gcp", nil},
+			},
+		},
+		{
+			name: "enum slice",
+			field: field{
+				docString: "Platforms are platforms.",
+				fieldType: types.String,
+				named:     platform,
+				slice:     true,
+			},
+			expected: &jsonSchema{
+				Description: "Platforms are platforms.",
+				Type:        []string{"array", "null"},
+				Items:       &jsonSchema{Type: "string", Enum: []any{"aws", "gcp"}},
+			},
+		},
+		{
+			name: "timestamp",
+			field: field{
+				docString:     "Time is a time.",
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionMicroseconds,
+			},
+			expected: &jsonSchema{
+				Description: "Time is a time.",
+				Comment:     "Unix time in microseconds",
+				Type:        []string{"integer", "null"},
+			},
+		},
+		{
+			name: "duration",
+			field: field{
+				docString:     "Duration is a duration.",
+				fieldType:     types.Int64,
+				timeKind:      timeKindDuration,
+				timePrecision: timePrecisionSeconds,
+			},
+			expected: &jsonSchema{
+				Description: "Duration is a duration.",
+				Comment:     "Duration in seconds",
+				Type:        []string{"integer", "null"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(getJSONSchemaFieldSchema(test.field)).To(Equal(test.expected))
+		})
+	}
+}
@@ -0,0 +1,245 @@
+//go:build generator
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// keyStyle is the naming convention of the attribute keys, derived from the names of the fields.
+type keyStyle string
+
+const (
+	// keyStylePascal keeps the names of the fields, like ClusterID.
+	keyStylePascal keyStyle = "pascal"
+	// keyStyleCamel converts the names of the fields to camelCase, like clusterID.
+	keyStyleCamel keyStyle = "camel"
+	// keyStyleSnake converts the names of the fields to snake_case, like cluster_id.
+	keyStyleSnake keyStyle = "snake"
+	// keyStyleDotted converts the names of the fields to lowercase words separated by dots, like cluster.id.
+	keyStyleDotted keyStyle = "dotted"
+)
+
+// keyStyles are the supported key styles.
+var keyStyles = []keyStyle{
+	keyStylePascal,
+	keyStyleCamel,
+	keyStyleSnake,
+	keyStyleDotted,
+}
+
+// splitWords splits the name of a field into words. Acronyms are kept together, so that ClusterID is split into
+// Cluster and ID, and HTTPRoutes into HTTP and Routes.
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var (
+		words []string
+		word  []rune
+	)
+
+	isPluralAcronym := func(i int) bool {
+		// the s in IDs belongs to the acronym
+		return runes[i+1] == 's' && (i+2 == len(runes) || !unicode.IsLower(runes[i+2]))
+	}
+
+	for i, r := range runes {
+		if r == '_' {
+			if len(word) > 0 {
+				words = append(words, string(word))
+				word = nil
+			}
+
+			continue
+		}
+
+		if len(word) > 0 && unicode.IsUpper(r) {
+			prev := runes[i-1]
+
+			lowerToUpper := unicode.IsLower(prev) || unicode.IsDigit(prev)
+			acronymEnd := unicode.IsUpper(prev) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) && !isPluralAcronym(i)
+
+			if lowerToUpper || acronymEnd {
+				words = append(words, string(word))
+				word = nil
+			}
+		}
+
+		word = append(word, r)
+	}
+
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	return words
+}
+
+// formatKey formats the name of a field as an attribute key in the style.
+func formatKey(name string, style keyStyle) string {
+	words := splitWords(name)
+
+	switch style {
+	case keyStylePascal:
+		return name
+	case keyStyleCamel:
+		words[0] = strings.ToLower(words[0])
+		return strings.Join(words, "")
+	case keyStyleSnake:
+		return strings.ToLower(strings.Join(words, "_"))
+	case keyStyleDotted:
+		return strings.ToLower(strings.Join(words, "."))
+	default:
+		panic(fmt.Sprintf("unexpected key style %q", style))
+	}
+}
+
+// getAvroName returns the name of the Avro field for the attribute key,
+// replacing the characters that are not allowed in Avro names (like dots) with underscores.
+func getAvroName(key string) string {
+	return strings.Map(
+		func(r rune) rune {
+			if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+				return r
+			}
+			return '_'
+		},
+		key,
+	)
+}
+
+// applyKeyStyle sets the attribute keys of the fields, including the fields of the embedded structs.
+// The keys that are not set by the telemetry tag are formatted in the style. The prefix is added to all keys.
+// It returns an error if the resulting keys or their Avro names are not unique or are reserved, like the names of
+// the fields of the envelope of the schemes, or if a key starts with the key of a map field followed by a dot,
+// which is the prefix of the attributes of the map.
+//
+// The attributes of embedded structs come from their Attributes method, so the embedded structs must be generated
+// with the same style and prefix, which checkEmbeddedStructKeys checks.
+func applyKeyStyle(fields []field, style keyStyle, prefix string, reservedKeys map[string]struct{}) ([]field, error) {
+	keyOwners := make(map[string]string)
+	avroNameOwners := make(map[string]string)
+
+	var applyRecursively func([]field) ([]field, error)
+	applyRecursively = func(fields []field) ([]field, error) {
+		result := make([]field, 0, len(fields))
+
+		for _, f := range fields {
+			if f.embeddedStruct {
+				embeddedFields, err := applyRecursively(f.embeddedStructFields)
+				if err != nil {
+					return nil, err
+				}
+
+				f.embeddedStructFields = embeddedFields
+				result = append(result, f)
+
+				continue
+			}
+
+			if f.key == "" {
+				f.key = formatKey(f.name, style)
+			}
+			f.key = prefix + f.key
+
+			if _, reserved := reservedKeys[f.key]; reserved {
+				return nil, fmt.Errorf("field %s: key %s is reserved", f.name, f.key)
+			}
+
+			if owner, exists := keyOwners[f.key]; exists {
+				return nil, fmt.Errorf("field %s: key %s is already used by field %s", f.name, f.key, owner)
+			}
+			keyOwners[f.key] = f.name
+
+			avroName := getAvroName(f.key)
+			if owner, exists := avroNameOwners[avroName]; exists {
+				return nil, fmt.Errorf(
+					"field %s: Avro name %s of key %s is already used by field %s",
+					f.name,
+					avroName,
+					f.key,
+					owner,
+				)
+			}
+			avroNameOwners[avroName] = f.name
+
+			result = append(result, f)
+		}
+
+		return result, nil
+	}
+
+	result, err := applyRecursively(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkMapKeyPrefixes(result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkMapKeyPrefixes checks that no key starts with the key of a map field followed by a dot, including the keys
+// of other map fields. The attributes of a map field are keyed by the key of the field, a dot and the map key,
+// so such a key would be read back as an entry of the map.
+func checkMapKeyPrefixes(fields []field) error {
+	var allFields []field
+
+	var flatten func([]field)
+	flatten = func(fields []field) {
+		for _, f := range fields {
+			if f.embeddedStruct {
+				flatten(f.embeddedStructFields)
+				continue
+			}
+
+			allFields = append(allFields, f)
+		}
+	}
+
+	flatten(fields)
+
+	for _, f := range allFields {
+		for _, m := range allFields {
+			if m.stringMap && strings.HasPrefix(f.attributeKey(), m.attributeKey()+".") {
+				return fmt.Errorf(
+					"field %s: key %s is in the attributes of map field %s",
+					f.name,
+					f.attributeKey(),
+					m.name,
+				)
+			}
+		}
+	}
+
+	return nil
+}
+
+// checkEmbeddedStructKeys checks that the embedded structs, including the nested ones, are generated in the same run
+// as the fields, so that their Attributes methods use the same key style and prefix. The embedded structs must be
+// among the types of the package in the package path.
+func checkEmbeddedStructKeys(fields []field, packagePath string, typeNames map[string]struct{}) error {
+	for _, f := range fields {
+		if !f.embeddedStruct {
+			continue
+		}
+
+		if _, generated := typeNames[f.name]; !generated || f.embeddedStructPackage != packagePath {
+			return fmt.Errorf(
+				"embedded struct %s must be generated in the same run to use a key style or prefix, "+
+					"because its attributes come from its Attributes method",
+				f.embeddedStructName(),
+			)
+		}
+
+		if err := checkEmbeddedStructKeys(f.embeddedStructFields, packagePath, typeNames); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
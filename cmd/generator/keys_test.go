@@ -0,0 +1,345 @@
+//go:build generator
+
+package main
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSplitWords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected []string
+	}{
+		{
+			name:     "ProjectName",
+			expected: []string{"Project", "Name"},
+		},
+		{
+			name:     "ClusterID",
+			expected: []string{"Cluster", "ID"},
+		},
+		{
+			name:     "HTTPRoutes",
+			expected: []string{"HTTP", "Routes"},
+		},
+		{
+			name:     "GatewayIDs",
+			expected: []string{"Gateway", "IDs"},
+		},
+		{
+			name:     "SomeUint32Map",
+			expected: []string{"Some", "Uint32", "Map"},
+		},
+		{
+			name:     "Some_Field",
+			expected: []string{"Some", "Field"},
+		},
+		{
+			name:     "ID",
+			expected: []string{"ID"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(splitWords(test.name)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestFormatKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		style    keyStyle
+		expected string
+	}{
+		{
+			name:     "ClusterID",
+			style:    keyStylePascal,
+			expected: "ClusterID",
+		},
+		{
+			name:     "ClusterID",
+			style:    keyStyleCamel,
+			expected: "clusterID",
+		},
+		{
+			name:     "HTTPRoutes",
+			style:    keyStyleCamel,
+			expected: "httpRoutes",
+		},
+		{
+			name:     "ClusterID",
+			style:    keyStyleSnake,
+			expected: "cluster_id",
+		},
+		{
+			name:     "ClusterNodeCount",
+			style:    keyStyleDotted,
+			expected: "cluster.node.count",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(string(test.style)+" "+test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(formatKey(test.name, test.style)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestApplyKeyStyle(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	fields := []field{
+		{name: "ClusterID"},
+		{name: "Platform", key: "k8s.platform"},
+		{
+			name:           "Embedded",
+			embeddedStruct: true,
+			embeddedStructFields: []field{
+				{name: "NodeCount"},
+			},
+		},
+	}
+
+	expectedFields := []field{
+		{name: "ClusterID", key: "ngf.cluster.id"},
+		{name: "Platform", key: "ngf.k8s.platform"},
+		{
+			name:           "Embedded",
+			embeddedStruct: true,
+			embeddedStructFields: []field{
+				{name: "NodeCount", key: "ngf.node.count"},
+			},
+		},
+	}
+
+	result, err := applyKeyStyle(fields, keyStyleDotted, "ngf.", schemeEnvelope{}.getReservedKeys())
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(expectedFields))
+	g.Expect(fields[0].key).To(BeEmpty(), "the fields must not be modified")
+}
+
+func TestApplyKeyStyleErrors(t *testing.T) {
+	t.Parallel()
+
+	customEnvelope := schemeEnvelope{
+		Fields: []envelopeField{
+			{Name: "timestamp", Type: "long"},
+			{Name: "kind", Type: "string", DataType: true},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		expectedErrMsg string
+		style          keyStyle
+		fields         []field
+		envelope       schemeEnvelope
+	}{
+		{
+			name:  "key collision",
+			style: keyStyleSnake,
+			fields: []field{
+				{name: "ClusterID"},
+				{name: "Cluster_ID"},
+			},
+			expectedErrMsg: "field Cluster_ID: key cluster_id is already used by field ClusterID",
+		},
+		{
+			name:  "key collision with embedded struct",
+			style: keyStyleCamel,
+			fields: []field{
+				{name: "Count"},
+				{
+					name:           "Embedded",
+					embeddedStruct: true,
+					embeddedStructFields: []field{
+						{name: "Other", key: "count"},
+					},
+				},
+			},
+			expectedErrMsg: "field Other: key count is already used by field Count",
+		},
+		{
+			name:  "Avro name collision",
+			style: keyStyleDotted,
+			fields: []field{
+				{name: "ClusterID"},
+				{name: "Other", key: "cluster_id"},
+			},
+			expectedErrMsg: "field Other: Avro name cluster_id of key cluster_id is already used by field ClusterID",
+		},
+		{
+			name:  "reserved key",
+			style: keyStyleCamel,
+			fields: []field{
+				{name: "DataType"},
+			},
+			expectedErrMsg: "field DataType: key dataType is reserved",
+		},
+		{
+			name:  "reserved key of custom envelope",
+			style: keyStyleCamel,
+			fields: []field{
+				{name: "DataType"},
+				{name: "EventTime"},
+				{name: "Timestamp"},
+			},
+			envelope:       customEnvelope,
+			expectedErrMsg: "field Timestamp: key timestamp is reserved",
+		},
+		{
+			name:  "data type key of custom envelope",
+			style: keyStyleSnake,
+			fields: []field{
+				{name: "Kind"},
+			},
+			envelope:       customEnvelope,
+			expectedErrMsg: "field Kind: key kind is reserved",
+		},
+		{
+			name:  "key in the attributes of map field",
+			style: keyStyleDotted,
+			fields: []field{
+				{name: "Resources", stringMap: true},
+				{name: "ResourcesTotal"},
+			},
+			expectedErrMsg: "field ResourcesTotal: key resources.total is in the attributes of map field Resources",
+		},
+		{
+			name:  "map field declared after the key in its attributes",
+			style: keyStyleDotted,
+			fields: []field{
+				{name: "ResourcesTotal"},
+				{name: "Resources", stringMap: true},
+			},
+			expectedErrMsg: "field ResourcesTotal: key resources.total is in the attributes of map field Resources",
+		},
+		{
+			name:  "map field in the attributes of map field",
+			style: keyStyleDotted,
+			fields: []field{
+				{name: "Resources", stringMap: true},
+				{
+					name:           "Embedded",
+					embeddedStruct: true,
+					embeddedStructFields: []field{
+						{name: "ResourcesByKind", stringMap: true},
+					},
+				},
+			},
+			expectedErrMsg: "field ResourcesByKind: key resources.by.kind is in the attributes of map field Resources",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := applyKeyStyle(test.fields, test.style, "", test.envelope.getReservedKeys())
+
+			g.Expect(err).To(MatchError(test.expectedErrMsg))
+		})
+	}
+}
+
+func TestGetAvroName(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	g.Expect(getAvroName("ngf.cluster.id")).To(Equal("ngf_cluster_id"))
+	g.Expect(getAvroName("ClusterID")).To(Equal("ClusterID"))
+}
+
+func TestCheckEmbeddedStructKeys(t *testing.T) {
+	t.Parallel()
+
+	const packagePath = "example.com/telemetry"
+
+	typeNames := map[string]struct{}{"Data": {}, "ClusterData": {}, "NodeData": {}}
+
+	tests := []struct {
+		name           string
+		expectedErrMsg string
+		fields         []field
+	}{
+		{
+			name:   "no embedded structs",
+			fields: []field{{name: "Count"}},
+		},
+		{
+			name: "embedded struct generated in the same run",
+			fields: []field{
+				{
+					name:                  "ClusterData",
+					embeddedStruct:        true,
+					embeddedStructPackage: packagePath,
+					embeddedStructFields: []field{
+						{name: "NodeData", embeddedStruct: true, embeddedStructPackage: packagePath},
+					},
+				},
+			},
+		},
+		{
+			name: "embedded struct of another package",
+			fields: []field{
+				{name: "ClusterData", embeddedStruct: true, embeddedStructPackage: "example.com/cluster"},
+			},
+			expectedErrMsg: "embedded struct cluster.ClusterData must be generated in the same run",
+		},
+		{
+			name: "embedded struct of the package that is not generated",
+			fields: []field{
+				{name: "OtherData", embeddedStruct: true, embeddedStructPackage: packagePath},
+			},
+			expectedErrMsg: "embedded struct telemetry.OtherData must be generated in the same run",
+		},
+		{
+			name: "nested embedded struct of another package",
+			fields: []field{
+				{
+					name:                  "ClusterData",
+					embeddedStruct:        true,
+					embeddedStructPackage: packagePath,
+					embeddedStructFields: []field{
+						{name: "NodeData", embeddedStruct: true, embeddedStructPackage: "example.com/node"},
+					},
+				},
+			},
+			expectedErrMsg: "embedded struct node.NodeData must be generated in the same run",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			err := checkEmbeddedStructKeys(test.fields, packagePath, typeNames)
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(ContainSubstring(test.expectedErrMsg)))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
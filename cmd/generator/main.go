@@ -3,129 +3,936 @@
 package main
 
 import (
+	"bytes"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 )
 
 var (
 	code                     = flag.Bool("code", true, "Generate code")
-	buildTags                = flag.String("build-tags", "", "Comma separated list of build tags expected in the source files that will be added to the generated code") //nolint:lll
-	scheme                   = flag.Bool("scheme", false, "Generate Avro scheme")
-	schemeNamespace          = flag.String("scheme-namespace", "gateway.nginx.org", "Scheme namespace; required when -scheme is set") //nolint:lll
-	schemeProtocol           = flag.String("scheme-protocol", "", "Scheme protocol; required when -scheme is set")
-	schemeDataFabricDataType = flag.String("scheme-df-datatype", "", "Scheme data fabric data type; required when -scheme is set") //nolint:lll
-	typeName                 = flag.String("type", "", "Type to generate; required")
+	buildTags                = flag.String("build-tags", "", "Comma separated list of build tags expected in the source files that are added to the generated code; if set, overrides the "+markerPrefix+markerBuildTags+" marker") //nolint:lll
+	scheme                   = flag.Bool("scheme", false, "Generate Avro scheme of all types; the scheme of a type is also generated when it has the "+markerPrefix+markerSchemeProtocol+" marker")                                 //nolint:lll
+	schemeNamespace          = flag.String("scheme-namespace", "gateway.nginx.org", "Scheme namespace; if set, overrides the "+markerPrefix+markerSchemeNamespace+" marker")                                                        //nolint:lll
+	schemeProtocol           = flag.String("scheme-protocol", "", "Scheme protocol; required when -scheme is set, unless set by the "+markerPrefix+markerSchemeProtocol+" marker")                                                  //nolint:lll
+	schemeDataFabricDataType = flag.String("scheme-df-datatype", "", "Scheme data fabric data type; required when -scheme is set, unless set by the "+markerPrefix+markerSchemeDataType+" marker")                                  //nolint:lll
+	typeNamesFlag            = flag.String("type", "", "Comma separated list of types to generate; required unless -all is set")                                                                                                    //nolint:lll
+	all                      = flag.Bool("all", false, "Generate all structs of the package annotated with the "+generateMarker+" comment")                                                                                         //nolint:lll
+	timePrecisionFlag        = flag.String("time-precision", string(timePrecisionMilliseconds), "Precision of time.Time and time.Duration fields: s, ms, us or ns")                                                                 //nolint:lll
+	keyStyleFlag             = flag.String("key-style", string(keyStylePascal), "Style of the attribute keys derived from the field names: pascal, camel, snake or dotted. Embedded structs must be in the same run unless pascal") //nolint:lll
+	output                   = flag.String("output", "", "Path of the generated code file or - for stdout; defaults to <type>_attributes_generated.go for a single type and <package>_attributes_generated.go otherwise")           //nolint:lll
+	schemeOutput             = flag.String("scheme-output", "", "Path of the generated scheme file or - for stdout; defaults to <type>.avdl. Only supported when a single scheme is generated")                                     //nolint:lll
+	jsonSchemaFlag           = flag.Bool("json-schema", false, "Generate JSON Schema of all types")
+	jsonSchemaOutput         = flag.String("json-schema-output", "", "Path of the generated JSON Schema file or - for stdout; defaults to <type>.schema.json. Only supported when a single type is generated")                                                                                                             //nolint:lll
+	avsc                     = flag.Bool("avsc", false, "Generate Avro JSON schema (.avsc) of the types whose Avro scheme is generated")                                                                                                                                                                                   //nolint:lll
+	avscOutput               = flag.String("avsc-output", "", "Path of the generated Avro JSON schema file or - for stdout; defaults to <type>.avsc. Only supported when a single scheme is generated")                                                                                                                    //nolint:lll
+	proto                    = flag.Bool("proto", false, "Generate Protobuf schema of all types, with a lock file that keeps the field numbers stable")                                                                                                                                                                    //nolint:lll
+	protoOutput              = flag.String("proto-output", "", "Path of the generated Protobuf schema file or - for stdout; defaults to <type>.proto. The lock file is <path>.lock, or <type>.proto.lock for stdout. Only supported when a single type is generated")                                                      //nolint:lll
+	docs                     = flag.Bool("docs", false, "Generate data dictionary of all types, which lists their fields with their types and descriptions")                                                                                                                                                               //nolint:lll
+	docsFormatFlag           = flag.String("docs-format", string(docsFormatMarkdown), "Format of the data dictionary: markdown or html")                                                                                                                                                                                   //nolint:lll
+	docsOutput               = flag.String("docs-output", "", "Path of the generated data dictionary file or - for stdout; defaults to <type>.md or <type>.html. Only supported when a single type is generated")                                                                                                          //nolint:lll
+	schemeEnvelopeFile       = flag.String("scheme-envelope", "", "Path to a YAML or JSON file with the envelope fields and annotations of the scheme records; defaults to the data fabric envelope")                                                                                                                      //nolint:lll
+	codeTemplateFile         = flag.String("code-template", "", "Path to a text/template file that replaces the code template, which it can wrap with {{ template \"default\" . }}; it receives the code template data and functions below")                                                                               //nolint:lll
+	schemeTemplateFile       = flag.String("scheme-template", "", "Path to a text/template file that replaces the Avro scheme template, which it can wrap with {{ template \"default\" . }}; it receives the scheme template data and functions below. The Avro JSON schema and compat ignore it")                         //nolint:lll
+	previous                 = flag.String("previous", "", "Path of the previously generated Avro JSON schema to check with the "+compatCommand+" command; defaults to the .avsc file of the type. Only supported when a single scheme is checked")                                                                        //nolint:lll
+	compatibilityFlag        = flag.String("compatibility", string(compatibilityBackward), "Compatibility checked by the "+compatCommand+" command: backward (the current scheme reads the data written with the previous one), forward (the previous scheme reads the data written with the current one) or full (both)") //nolint:lll
+	check                    = flag.Bool("check", os.Getenv(checkEnv) == "true", checkUsage)
+	configFile               = flag.String("config", "", "Path to a YAML or JSON config file that describes the packages to generate; the flags override its settings") //nolint:lll
+	keyPrefix                = flag.String("key-prefix", "", "Prefix added to all attribute keys. Embedded structs must be generated in the same run")                  //nolint:lll
 )
 
-func exitWithError(err error) {
-	fmt.Fprintln(os.Stderr, "error: "+err.Error())
-	os.Exit(1)
-}
+// checkEnv is the environment variable that enables the check mode by default.
+const checkEnv = "TELEMETRY_GENERATOR_CHECK"
+
+const checkUsage = "Check that the generated files are up to date instead of writing them; " +
+	"prints the diffs and fails if they are not. Defaults to true when " + checkEnv + "=true, " +
+	"so that go:generate directives can be checked"
+
+// stdoutPath is the output path that writes the output to stdout.
+const stdoutPath = "-"
+
+// logWriter is where the progress of the generation is logged.
+// It is stderr when an output is written to stdout, so that the output can be piped.
+var logWriter io.Writer = os.Stdout
+
+// errInvalidFlags is returned when the flags are invalid, so that the usage is printed.
+var errInvalidFlags = errors.New("invalid flags")
+
+func validateFlags() error {
+	if *typeNamesFlag == "" && !*all {
+		return errInvalidFlags
+	}
+
+	if !slices.Contains(timePrecisions, timePrecision(*timePrecisionFlag)) {
+		return errInvalidFlags
+	}
+
+	if !slices.Contains(keyStyles, keyStyle(*keyStyleFlag)) {
+		return errInvalidFlags
+	}
+
+	if !slices.Contains(docsFormats, docsFormat(*docsFormatFlag)) {
+		return errInvalidFlags
+	}
+
+	if !slices.Contains(compatibilities, compatibility(*compatibilityFlag)) {
+		return errInvalidFlags
+	}
 
-func exitWithUsage() {
-	flag.Usage()
-	os.Exit(1)
+	return nil
 }
 
-func validateFlags() {
-	if *typeName == "" {
-		exitWithUsage()
+func main() {
+	flag.Usage = usage
+
+	args := os.Args[1:]
+
+	command := run
+	if len(args) > 0 && args[0] == compatCommand {
+		command = runCompat
+		args = args[1:]
 	}
 
-	if *scheme {
-		if *schemeNamespace == "" {
-			exitWithUsage()
-		}
-		if *schemeProtocol == "" {
-			exitWithUsage()
-		}
-		if *schemeDataFabricDataType == "" {
-			exitWithUsage()
+	flag.CommandLine.Parse(args) //nolint:errcheck // the command line exits on errors
+
+	// the errors are handled here, so that os.Exit doesn't skip the deferred functions of the generation
+	if err := command(); err != nil {
+		if errors.Is(err, errInvalidFlags) {
+			flag.Usage()
+		} else {
+			fmt.Fprintln(os.Stderr, "error: "+err.Error())
 		}
+
+		os.Exit(1)
 	}
 }
 
-func main() {
-	flag.Parse()
+func usage() {
+	out := flag.CommandLine.Output()
+
+	fmt.Fprintf(out, "Usage: %s [%s] [flags]\n\n", filepath.Base(os.Args[0]), compatCommand)
+	fmt.Fprintln(out, "Generates the code and the schemes of the structs of the package in the working directory.")
+	fmt.Fprintf(out, "The %s command checks that the current schemes are compatible with the previous ones instead.\n\n",
+		compatCommand)
+	fmt.Fprintln(out, "The settings are applied in this order of precedence: the flags set on the command line,")
+	fmt.Fprint(out, "the config of the types, the markers of the structs, the config of the package and the defaults.\n\n")
+	fmt.Fprintln(out, "Flags:")
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "\nTemplate functions:")
+
+	for _, f := range templateFuncs {
+		fmt.Fprintf(out, "  %s\n    \t%s\n", f.name, f.description)
+	}
+
+	printTemplateFields(out, "code", codeTemplateFields)
+	printTemplateFields(out, "scheme", schemeTemplateFields)
+}
+
+// printTemplateFields prints the fields of the data of the template in the usage.
+func printTemplateFields(out io.Writer, name string, fields []templateField) {
+	fmt.Fprintf(out, "\nData of the %s template:\n", name)
+
+	for _, f := range fields {
+		fmt.Fprintf(out, "  .%s\n    \t%s\n", f.name, f.description)
+	}
+}
+
+// loadedTypes are the parsed types of the package with their settings.
+type loadedTypes struct {
+	typeCfgs map[string]typeConfig
+	// commandLineFlags are the names of the flags set on the command line, which override the markers.
+	commandLineFlags map[string]struct{}
+	tags             string
+	codeFileName     string
+	// envelope is the envelope of the scheme records, unless overridden by the config of a type.
+	envelope schemeEnvelope
+	result   parsingResult
+}
+
+// loadTypes applies the config and the flags and parses the types of the package in the working directory.
+func loadTypes() (loadedTypes, error) {
+	// the config sets the flags that are not set on the command line, so they are recorded before applying it
+	commandLineFlags := getCommandLineFlags()
+
+	var pkgCfg packageConfig
+
+	if *configFile != "" {
+		var err error
+		if pkgCfg, err = applyConfig(*configFile); err != nil {
+			return loadedTypes{}, err
+		}
+	}
+
+	if err := validateFlags(); err != nil {
+		return loadedTypes{}, err
+	}
 
-	validateFlags()
+	envelope, err := resolveSchemeEnvelope(pkgCfg.Scheme.Envelope)
+	if err != nil {
+		return loadedTypes{}, err
+	}
 
 	pkgName := os.Getenv("GOPACKAGE")
 	if pkgName == "" {
-		exitWithError(errors.New("GOPACKAGE is not set"))
+		return loadedTypes{}, errors.New("GOPACKAGE is not set")
+	}
+
+	typeCfgs := make(map[string]typeConfig, len(pkgCfg.Types))
+	for _, t := range pkgCfg.Types {
+		typeCfgs[t.Name] = t
+	}
+
+	outputFlags := []string{*output, *schemeOutput, *avscOutput, *jsonSchemaOutput, *protoOutput, *docsOutput}
+	if isStdoutUsed(outputFlags, typeCfgs) {
+		logWriter = os.Stderr
+	}
+
+	tags, err := resolveBuildTags(pkgName, commandLineFlags)
+	if err != nil {
+		return loadedTypes{}, err
 	}
 
 	var buildFlags []string
-	if *buildTags != "" {
-		buildFlags = []string{"-tags=" + *buildTags}
+	if tags != "" {
+		buildFlags = []string{"-tags=" + tags}
+	}
+
+	var typeNames []string
+	if *typeNamesFlag != "" {
+		typeNames, err = parseTypeNames(*typeNamesFlag)
+		if err != nil {
+			return loadedTypes{}, err
+		}
 	}
 
 	cfg := parsingConfig{
-		pkgName:    pkgName,
-		typeName:   *typeName,
-		buildFlags: buildFlags,
+		pkgName:       pkgName,
+		typeNames:     typeNames,
+		buildFlags:    buildFlags,
+		timePrecision: timePrecision(*timePrecisionFlag),
+		discover:      *all,
 	}
 
 	result, err := parse(cfg)
 	if err != nil {
-		exitWithError(fmt.Errorf("failed to parse struct: %w", err))
+		return loadedTypes{}, fmt.Errorf("failed to parse struct: %w", err)
+	}
+
+	return loadedTypes{
+		typeCfgs:         typeCfgs,
+		commandLineFlags: commandLineFlags,
+		tags:             tags,
+		codeFileName:     getCodeFileName(*output, pkgName, typeNames, *all),
+		envelope:         envelope,
+		result:           result,
+	}, nil
+}
+
+// parseTypeNames parses the comma separated list of the type names of the -type flag. The spaces around the names
+// are trimmed, and the names must be non-empty and unique.
+func parseTypeNames(value string) ([]string, error) {
+	names := strings.Split(value, ",")
+	seen := make(map[string]struct{}, len(names))
+
+	for i, name := range names {
+		name = strings.TrimSpace(name)
+
+		if name == "" {
+			return nil, fmt.Errorf("invalid -type %q: type name %d is empty", value, i+1)
+		}
+
+		if _, exists := seen[name]; exists {
+			return nil, fmt.Errorf("invalid -type %q: type %s is listed more than once", value, name)
+		}
+
+		seen[name] = struct{}{}
+		names[i] = name
+	}
+
+	return names, nil
+}
+
+// resolveSchemeEnvelope returns the envelope of the scheme records set by the -scheme-envelope flag, which overrides
+// the envelope of the config, or the default envelope.
+func resolveSchemeEnvelope(cfgEnvelope *schemeEnvelope) (schemeEnvelope, error) {
+	switch {
+	case *schemeEnvelopeFile != "":
+		return loadSchemeEnvelope(*schemeEnvelopeFile)
+	case cfgEnvelope != nil:
+		return *cfgEnvelope, nil
+	default:
+		return schemeEnvelope{}, nil
+	}
+}
+
+// run generates the outputs. The outputs are rendered in memory and written only if the generation of all of them
+// succeeds, so that a failure doesn't leave partial outputs.
+func run() error {
+	loaded, err := loadTypes()
+	if err != nil {
+		return err
+	}
+
+	outputs, err := renderOutputs(loaded)
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		return checkGeneratedOutputs(outputs)
+	}
+
+	return writeOutputs(outputs)
+}
+
+// runCompat checks that the schemes of the parsed types are compatible with their previously generated schemes,
+// printing the breaking changes.
+func runCompat() error {
+	loaded, err := loadTypes()
+	if err != nil {
+		return err
+	}
+
+	_, schemeCfgs, err := prepareTypes(loaded)
+	if err != nil {
+		return err
+	}
+
+	if len(schemeCfgs) == 0 {
+		return fmt.Errorf("no schemes to check; set -scheme or the %s%s marker", markerPrefix, markerSchemeProtocol)
+	}
+
+	if *previous != "" && len(schemeCfgs) > 1 {
+		return errors.New("-previous is only supported when a single scheme is checked")
+	}
+
+	var incompatible []string
+
+	for _, schemeCfg := range schemeCfgs {
+		previousPath := withDefault(*previous, getAvscFileName(*avscOutput, schemeCfg.record, loaded.typeCfgs))
+		if previousPath == stdoutPath {
+			return fmt.Errorf("the Avro JSON schema of struct %s is written to stdout; set -previous", schemeCfg.record)
+		}
+
+		fmt.Fprintf(logWriter, "Checking compatibility of scheme of struct %s with %s\n", schemeCfg.record, previousPath)
+
+		changes, err := checkSchemeCompatibility(previousPath, schemeCfg, compatibility(*compatibilityFlag))
+		if err != nil {
+			return fmt.Errorf("failed to check compatibility of scheme of struct %s: %w", schemeCfg.record, err)
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		incompatible = append(incompatible, schemeCfg.record)
+
+		fmt.Fprintf(os.Stdout, "Breaking changes of struct %s compared to %s:\n", schemeCfg.record, previousPath)
+		for _, change := range changes {
+			fmt.Fprintf(os.Stdout, "  - %s\n", change)
+		}
+	}
+
+	if len(incompatible) > 0 {
+		return fmt.Errorf("schemes are not compatible: %s", strings.Join(incompatible, ", "))
+	}
+
+	fmt.Fprintln(logWriter, "Schemes are compatible")
+
+	return nil
+}
+
+// renderOutputs renders the code and the schemes of the parsed types in memory.
+func renderOutputs(loaded loadedTypes) ([]generatedOutput, error) {
+	codeGenTypes, schemeCfgs, err := prepareTypes(loaded)
+	if err != nil {
+		return nil, err
 	}
 
-	fmt.Printf("Successfully parsed struct %s\n", *typeName)
+	if err := validateOutputFlags(len(codeGenTypes), len(schemeCfgs)); err != nil {
+		return nil, err
+	}
+
+	var outputs []generatedOutput
 
 	if *code {
-		fmt.Println("Generating code")
+		codeOutput, err := renderCode(loaded.result.packagePath, loaded.tags, loaded.codeFileName, codeGenTypes)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, codeOutput)
+	}
+
+	schemeOutputs, err := renderSchemes(schemeCfgs, loaded.typeCfgs)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs = append(outputs, schemeOutputs...)
+
+	if *jsonSchemaFlag {
+		jsonSchemaOutputs, err := renderJSONSchemas(codeGenTypes, loaded.typeCfgs)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, jsonSchemaOutputs...)
+	}
+
+	if *proto {
+		protoOutputs, err := renderProtos(codeGenTypes, loaded.typeCfgs)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, protoOutputs...)
+	}
+
+	if *docs {
+		docsOutputs, err := renderDocs(codeGenTypes, loaded.typeCfgs)
+		if err != nil {
+			return nil, err
+		}
+
+		outputs = append(outputs, docsOutputs...)
+	}
+
+	return outputs, nil
+}
+
+// validateOutputFlags validates that the output flags that set the path of a single file are only set when a single
+// file of their kind is generated.
+func validateOutputFlags(typeCount, schemeCount int) error {
+	switch {
+	case *schemeOutput != "" && schemeCount > 1:
+		return errors.New("-scheme-output is only supported when a single scheme is generated")
+	case *avscOutput != "" && schemeCount > 1:
+		return errors.New("-avsc-output is only supported when a single scheme is generated")
+	case *jsonSchemaOutput != "" && typeCount > 1:
+		return errors.New("-json-schema-output is only supported when a single type is generated")
+	case *protoOutput != "" && typeCount > 1:
+		return errors.New("-proto-output is only supported when a single type is generated")
+	case *docsOutput != "" && typeCount > 1:
+		return errors.New("-docs-output is only supported when a single type is generated")
+	default:
+		return nil
+	}
+}
+
+// renderCode renders the code of the types.
+func renderCode(packagePath, tags, codeFileName string, codeGenTypes []codeGenType) (generatedOutput, error) {
+	fmt.Fprintln(logWriter, "Generating code")
+
+	var codeGenBuildTags string
+	if tags != "" {
+		codeGenBuildTags = strings.ReplaceAll(tags, ",", " && ")
+	}
+
+	customTemplate, err := loadTemplate("code", *codeTemplateFile)
+	if err != nil {
+		return generatedOutput{}, err
+	}
+
+	codeCfg := codeGenConfig{
+		packagePath: packagePath,
+		buildTags:   codeGenBuildTags,
+		template:    customTemplate,
+		types:       codeGenTypes,
+	}
+
+	var buf bytes.Buffer
+
+	if err := generateCode(&buf, codeCfg); err != nil {
+		return generatedOutput{}, fmt.Errorf("failed to generate code: %w", err)
+	}
+
+	return generatedOutput{path: codeFileName, content: buf.Bytes()}, nil
+}
+
+// renderSchemes renders the Avro schemes and, if enabled, the Avro JSON schemas.
+func renderSchemes(schemeCfgs []schemeGenConfig, typeCfgs map[string]typeConfig) ([]generatedOutput, error) {
+	customTemplate, err := loadTemplate("scheme", *schemeTemplateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var outputs []generatedOutput
+
+	for _, schemeCfg := range schemeCfgs {
+		fmt.Fprintf(logWriter, "Generating scheme of struct %s\n", schemeCfg.record)
+
+		schemeCfg.template = customTemplate
+
+		var buf bytes.Buffer
 
-		fileName := strings.ToLower(*typeName) + "_attributes_generated.go"
+		if err := generateScheme(&buf, schemeCfg); err != nil {
+			return nil, fmt.Errorf("failed to generate scheme: %w", err)
+		}
+
+		outputs = append(outputs, generatedOutput{
+			path:    getSchemeFileName(*schemeOutput, schemeCfg.record, typeCfgs),
+			content: buf.Bytes(),
+		})
+
+		if !*avsc {
+			continue
+		}
+
+		fmt.Fprintf(logWriter, "Generating Avro JSON schema of struct %s\n", schemeCfg.record)
+
+		var avscBuf bytes.Buffer
+
+		if err := generateAvsc(&avscBuf, schemeCfg); err != nil {
+			return nil, fmt.Errorf("failed to generate Avro JSON schema: %w", err)
+		}
+
+		outputs = append(outputs, generatedOutput{
+			path:    getAvscFileName(*avscOutput, schemeCfg.record, typeCfgs),
+			content: avscBuf.Bytes(),
+		})
+	}
+
+	return outputs, nil
+}
+
+// renderJSONSchemas renders the JSON Schemas of the types.
+func renderJSONSchemas(codeGenTypes []codeGenType, typeCfgs map[string]typeConfig) ([]generatedOutput, error) {
+	outputs := make([]generatedOutput, 0, len(codeGenTypes))
+
+	for _, t := range codeGenTypes {
+		fmt.Fprintf(logWriter, "Generating JSON schema of struct %s\n", t.typeName)
+
+		jsonSchemaCfg := jsonSchemaGenConfig{
+			dataFabricDataType: t.schemeDataType,
+			record:             t.typeName,
+			fields:             t.fields,
+			envelope:           t.schemeEnvelope,
+		}
+
+		var buf bytes.Buffer
+
+		if err := generateJSONSchema(&buf, jsonSchemaCfg); err != nil {
+			return nil, fmt.Errorf("failed to generate JSON schema: %w", err)
+		}
+
+		outputs = append(outputs, generatedOutput{
+			path:    getJSONSchemaFileName(*jsonSchemaOutput, t.typeName, typeCfgs),
+			content: buf.Bytes(),
+		})
+	}
+
+	return outputs, nil
+}
+
+// renderProtos renders the Protobuf schemas of the types and their lock files, which keep the field numbers
+// of the existing lock files.
+func renderProtos(codeGenTypes []codeGenType, typeCfgs map[string]typeConfig) ([]generatedOutput, error) {
+	outputs := make([]generatedOutput, 0, 2*len(codeGenTypes))
+
+	for _, t := range codeGenTypes {
+		fmt.Fprintf(logWriter, "Generating Protobuf schema of struct %s\n", t.typeName)
+
+		protoFileName := getProtoFileName(*protoOutput, t.typeName, typeCfgs)
+		lockFileName := getProtoLockFileName(t.typeName, protoFileName)
+
+		lock, err := loadProtoLock(lockFileName)
+		if err != nil {
+			return nil, err
+		}
+
+		protoCfg := protoGenConfig{
+			lock:      lock,
+			namespace: t.schemeNamespace,
+			record:    t.typeName,
+			fields:    t.fields,
+		}
+
+		var buf, lockBuf bytes.Buffer
 
-		file, err := os.Create(fileName)
+		newLock, err := generateProto(&buf, protoCfg)
 		if err != nil {
-			exitWithError(fmt.Errorf("failed to create file: %w", err))
+			return nil, fmt.Errorf("failed to generate Protobuf schema: %w", err)
+		}
+
+		if err := encodeProtoLock(&lockBuf, newLock); err != nil {
+			return nil, err
+		}
+
+		outputs = append(
+			outputs,
+			generatedOutput{path: protoFileName, content: buf.Bytes()},
+			generatedOutput{path: lockFileName, content: lockBuf.Bytes()},
+		)
+	}
+
+	return outputs, nil
+}
+
+// renderDocs renders the data dictionaries of the types.
+func renderDocs(codeGenTypes []codeGenType, typeCfgs map[string]typeConfig) ([]generatedOutput, error) {
+	outputs := make([]generatedOutput, 0, len(codeGenTypes))
+
+	for _, t := range codeGenTypes {
+		fmt.Fprintf(logWriter, "Generating data dictionary of struct %s\n", t.typeName)
+
+		docsCfg := docsGenConfig{
+			format:   docsFormat(*docsFormatFlag),
+			record:   t.typeName,
+			fields:   t.fields,
+			envelope: t.schemeEnvelope,
 		}
-		defer file.Close()
 
-		var codeGenBuildTags string
-		if *buildTags != "" {
-			codeGenBuildTags = strings.ReplaceAll(*buildTags, ",", " && ")
+		var buf bytes.Buffer
+
+		if err := generateDocs(&buf, docsCfg); err != nil {
+			return nil, fmt.Errorf("failed to generate data dictionary: %w", err)
 		}
 
-		codeCfg := codeGenConfig{
-			packagePath:    result.packagePath,
-			typeName:       *typeName,
-			schemeDataType: *schemeDataFabricDataType,
-			fields:         result.fields,
-			buildTags:      codeGenBuildTags,
+		outputs = append(outputs, generatedOutput{
+			path:    getDocsFileName(*docsOutput, t.typeName, docsCfg.format, typeCfgs),
+			content: buf.Bytes(),
+		})
+	}
+
+	return outputs, nil
+}
+
+// checkGeneratedOutputs checks that the files on disk are up to date, printing the diffs of the outdated files.
+func checkGeneratedOutputs(outputs []generatedOutput) error {
+	outdated, err := checkOutputs(os.Stdout, outputs)
+	if err != nil {
+		return err
+	}
+
+	if len(outdated) > 0 {
+		return fmt.Errorf("generated files are out of date: %s", strings.Join(outdated, ", "))
+	}
+
+	fmt.Fprintln(logWriter, "Generated files are up to date")
+
+	return nil
+}
+
+// getCodeFileName returns the path of the generated code file, which is set by the -output flag as outputPath
+// or else derived from the names of the package and the types. All is true when the -all flag is set.
+func getCodeFileName(outputPath, pkgName string, typeNames []string, all bool) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	// the code of multiple types is generated into a single file per package
+	if len(typeNames) == 1 && !all {
+		return strings.ToLower(typeNames[0]) + "_attributes_generated.go"
+	}
+
+	return strings.ToLower(pkgName) + "_attributes_generated.go"
+}
+
+// getSchemeFileName returns the path of the generated scheme file of the type, which is set by the -scheme-output
+// flag as outputPath or the config of the type, or else derived from the name of the type.
+func getSchemeFileName(outputPath, typeName string, typeCfgs map[string]typeConfig) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	return withDefault(typeCfgs[typeName].Scheme.Output, strings.ToLower(typeName)+".avdl")
+}
+
+// getAvscFileName returns the path of the generated Avro JSON schema file of the type, which is set by
+// the -avsc-output flag as outputPath or the config of the type, or else derived from the name of the type.
+func getAvscFileName(outputPath, typeName string, typeCfgs map[string]typeConfig) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	return withDefault(typeCfgs[typeName].Scheme.AvscOutput, strings.ToLower(typeName)+".avsc")
+}
+
+// getJSONSchemaFileName returns the path of the generated JSON Schema file of the type, which is set by
+// the -json-schema-output flag as outputPath or the config of the type, or else derived from the name of the type.
+func getJSONSchemaFileName(outputPath, typeName string, typeCfgs map[string]typeConfig) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	return withDefault(typeCfgs[typeName].Scheme.JSONSchemaOutput, strings.ToLower(typeName)+".schema.json")
+}
+
+// getProtoFileName returns the path of the generated Protobuf schema file of the type, which is set by
+// the -proto-output flag as outputPath or the config of the type, or else derived from the name of the type.
+func getProtoFileName(outputPath, typeName string, typeCfgs map[string]typeConfig) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	return withDefault(typeCfgs[typeName].Scheme.ProtoOutput, strings.ToLower(typeName)+".proto")
+}
+
+// getProtoLockFileName returns the path of the lock file of the Protobuf schema file, which is next to it.
+// The lock file can't be written to stdout, because it is read by the next generation.
+func getProtoLockFileName(typeName, protoFileName string) string {
+	if protoFileName == stdoutPath {
+		return strings.ToLower(typeName) + ".proto.lock"
+	}
+
+	return protoFileName + ".lock"
+}
+
+// getDocsFileName returns the path of the generated data dictionary file of the type, which is set by
+// the -docs-output flag as outputPath or the config of the type, or else derived from the name of the type
+// and the format.
+func getDocsFileName(outputPath, typeName string, format docsFormat, typeCfgs map[string]typeConfig) string {
+	if outputPath != "" {
+		return outputPath
+	}
+
+	return withDefault(typeCfgs[typeName].Scheme.DocsOutput, strings.ToLower(typeName)+docsFileExtensions[format])
+}
+
+// isStdoutUsed returns true if any output is written to stdout, where outputFlags are the paths set by the output
+// flags and typeCfgs are the types with their output paths from the config.
+func isStdoutUsed(outputFlags []string, typeCfgs map[string]typeConfig) bool {
+	if slices.Contains(outputFlags, stdoutPath) {
+		return true
+	}
+
+	for _, t := range typeCfgs {
+		paths := []string{
+			t.Scheme.Output,
+			t.Scheme.AvscOutput,
+			t.Scheme.JSONSchemaOutput,
+			t.Scheme.ProtoOutput,
+			t.Scheme.DocsOutput,
 		}
 
-		if err := generateCode(file, codeCfg); err != nil {
-			exitWithError(fmt.Errorf("failed to generate code: %w", err))
+		for _, path := range paths {
+			if path == stdoutPath {
+				return true
+			}
 		}
 	}
 
-	if *scheme {
-		fmt.Println("Generating scheme")
+	return false
+}
+
+// getCommandLineFlags returns the names of the flags set on the command line.
+func getCommandLineFlags() map[string]struct{} {
+	names := make(map[string]struct{})
+
+	flag.Visit(func(f *flag.Flag) {
+		names[f.Name] = struct{}{}
+	})
+
+	return names
+}
+
+// resolveSetting returns the value of the flag when it is set on the command line, or else the value of the markers,
+// or else the value of the flag set by the config or its default.
+func resolveSetting(commandLineFlags map[string]struct{}, name, markerValue string) string {
+	value := flag.Lookup(name).Value.String()
+
+	if _, set := commandLineFlags[name]; set {
+		return value
+	}
+
+	return withDefault(markerValue, value)
+}
+
+// resolveBuildTags returns the build tags set by the -build-tags flag on the command line, by the markers of the
+// structs of the package or by the config.
+func resolveBuildTags(pkgName string, commandLineFlags map[string]struct{}) (string, error) {
+	if _, set := commandLineFlags["build-tags"]; set {
+		return *buildTags, nil
+	}
+
+	// the build tags are needed to load the package, so their markers are scanned before loading it
+	scannedTags, err := scanBuildTags(".", pkgName)
+	if err != nil {
+		return "", fmt.Errorf("failed to scan markers: %w", err)
+	}
+
+	return withDefault(scannedTags, *buildTags), nil
+}
+
+// prepareTypes applies the flags, the markers and the configs of the types to the parsed types, returning the types
+// to generate code for and the configs of the schemes to generate. The envelope of the loaded types is the envelope
+// of the scheme records, unless overridden by the config of a type.
+func prepareTypes(loaded loadedTypes) ([]codeGenType, []schemeGenConfig, error) {
+	result, typeCfgs := loaded.result, loaded.typeCfgs
+
+	codeGenTypes := make([]codeGenType, 0, len(result.types))
+	var schemeCfgs []schemeGenConfig
+
+	typeNames := make(map[string]struct{}, len(result.types))
+	for _, t := range result.types {
+		typeNames[t.name] = struct{}{}
+	}
+
+	for _, t := range result.types {
+		fmt.Fprintf(logWriter, "Successfully parsed struct %s\n", t.name)
+
+		if keyStyle(*keyStyleFlag) != keyStylePascal || *keyPrefix != "" {
+			if err := checkEmbeddedStructKeys(t.fields, result.packagePath, typeNames); err != nil {
+				return nil, nil, fmt.Errorf("failed to apply key style to struct %s: %w", t.name, err)
+			}
+		}
 
-		fileName := strings.ToLower(*typeName) + ".avdl"
+		typeEnvelope := loaded.envelope
+		if typeCfgs[t.name].Scheme.Envelope != nil {
+			typeEnvelope = *typeCfgs[t.name].Scheme.Envelope
+		}
 
-		file, err := os.Create(fileName)
+		fields, err := applyKeyStyle(t.fields, keyStyle(*keyStyleFlag), *keyPrefix, typeEnvelope.getReservedKeys())
 		if err != nil {
-			exitWithError(fmt.Errorf("failed to create file: %w", err))
+			return nil, nil, fmt.Errorf("failed to apply key style to struct %s: %w", t.name, err)
+		}
+
+		// the config of the type overrides its markers, and the command line overrides both
+		markers := mergeTypeMarkers(t.markers, typeCfgs[t.name].typeMarkers())
+
+		namespace := resolveSetting(loaded.commandLineFlags, "scheme-namespace", markers.schemeNamespace)
+		dataType := resolveSetting(loaded.commandLineFlags, "scheme-df-datatype", markers.schemeDataType)
+
+		codeGenTypes = append(codeGenTypes, codeGenType{
+			typeName:        t.name,
+			schemeNamespace: namespace,
+			schemeDataType:  dataType,
+			schemeEnvelope:  typeEnvelope,
+			fields:          fields,
+		})
+
+		if !*scheme && markers.schemeProtocol == "" {
+			continue
 		}
-		defer file.Close()
 
 		schemeCfg := schemeGenConfig{
-			namespace:          *schemeNamespace,
-			protocol:           *schemeProtocol,
-			dataFabricDataType: *schemeDataFabricDataType,
-			record:             *typeName,
-			fields:             result.fields,
+			namespace:          namespace,
+			protocol:           resolveSetting(loaded.commandLineFlags, "scheme-protocol", markers.schemeProtocol),
+			dataFabricDataType: dataType,
+			record:             t.name,
+			fields:             fields,
+			envelope:           typeEnvelope,
+		}
+
+		if err := validateSchemeGenConfig(schemeCfg); err != nil {
+			return nil, nil, fmt.Errorf("invalid scheme of struct %s: %w", t.name, err)
+		}
+
+		schemeCfgs = append(schemeCfgs, schemeCfg)
+	}
+
+	return codeGenTypes, schemeCfgs, nil
+}
+
+// applyConfig loads the config file and sets the flags that are not set to the settings of the package
+// in the working directory, returning the config of the package.
+func applyConfig(path string) (packageConfig, error) {
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return packageConfig{}, err
+	}
+
+	configDir, err := filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return packageConfig{}, fmt.Errorf("failed to get directory of config: %w", err)
+	}
+
+	workingDir, err := os.Getwd()
+	if err != nil {
+		return packageConfig{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	pkgCfg, err := cfg.findPackage(configDir, workingDir)
+	if err != nil {
+		return packageConfig{}, err
+	}
+
+	settings := cfg.settings(pkgCfg)
+
+	typeNames := make([]string, 0, len(pkgCfg.Types))
+	for _, t := range pkgCfg.Types {
+		typeNames = append(typeNames, t.Name)
+	}
+
+	values := map[string]string{
+		"build-tags":         settings.BuildTags,
+		"key-style":          settings.KeyStyle,
+		"key-prefix":         settings.KeyPrefix,
+		"time-precision":     settings.TimePrecision,
+		"docs-format":        settings.DocsFormat,
+		"scheme-namespace":   settings.Scheme.Namespace,
+		"scheme-protocol":    settings.Scheme.Protocol,
+		"scheme-df-datatype": settings.Scheme.DataType,
+		"type":               strings.Join(typeNames, ","),
+		"output":             pkgCfg.Output,
+	}
+
+	if settings.Avsc {
+		values["avsc"] = "true"
+	}
+
+	if settings.JSONSchema {
+		values["json-schema"] = "true"
+	}
+
+	if settings.Proto {
+		values["proto"] = "true"
+	}
+
+	if settings.Docs {
+		values["docs"] = "true"
+	}
+
+	// the flags set on the command line override the config
+	flag.Visit(func(f *flag.Flag) {
+		delete(values, f.Name)
+	})
+
+	for name, value := range values {
+		if value == "" {
+			continue
 		}
 
-		if err := generateScheme(file, schemeCfg); err != nil {
-			exitWithError(fmt.Errorf("failed to generate scheme: %w", err))
+		if err := flag.Set(name, value); err != nil {
+			return packageConfig{}, fmt.Errorf("failed to apply config: %w", err)
 		}
 	}
+
+	// the envelope can't be set as a flag, so the config of the package carries the envelope of its settings
+	pkgCfg.Scheme.Envelope = settings.Scheme.Envelope
+
+	return pkgCfg, nil
+}
+
+// withDefault returns the value or the default value if the value is empty.
+func withDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// validateSchemeGenConfig validates that the settings of the scheme, which come from either the flags or the markers,
+// are set.
+func validateSchemeGenConfig(cfg schemeGenConfig) error {
+	switch {
+	case cfg.namespace == "":
+		return fmt.Errorf("namespace is required; set -scheme-namespace or the %s%s marker",
+			markerPrefix, markerSchemeNamespace)
+	case cfg.protocol == "":
+		return fmt.Errorf("protocol is required; set -scheme-protocol or the %s%s marker",
+			markerPrefix, markerSchemeProtocol)
+	case cfg.dataFabricDataType == "" && cfg.envelope.getDataTypeAnnotation() != "":
+		return fmt.Errorf("data fabric data type is required; set -scheme-df-datatype or the %s%s marker",
+			markerPrefix, markerSchemeDataType)
+	default:
+		return checkEnvelopeFields(cfg.envelope, cfg.fields)
+	}
 }
@@ -0,0 +1,367 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGetCodeFileName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		outputPath string
+		expected   string
+		typeNames  []string
+		all        bool
+	}{
+		{
+			name:      "single type",
+			typeNames: []string{"Data"},
+			expected:  "data_attributes_generated.go",
+		},
+		{
+			name:      "multiple types",
+			typeNames: []string{"Data", "MoreData"},
+			expected:  "telemetry_attributes_generated.go",
+		},
+		{
+			name:      "single type with all",
+			typeNames: []string{"Data"},
+			all:       true,
+			expected:  "telemetry_attributes_generated.go",
+		},
+		{
+			name:       "output flag",
+			outputPath: "attributes.go",
+			typeNames:  []string{"Data"},
+			expected:   "attributes.go",
+		},
+		{
+			name:       "stdout",
+			outputPath: stdoutPath,
+			typeNames:  []string{"Data", "MoreData"},
+			expected:   stdoutPath,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			fileName := getCodeFileName(test.outputPath, "Telemetry", test.typeNames, test.all)
+
+			g.Expect(fileName).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestParseTypeNames(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		value          string
+		expectedErrMsg string
+		expected       []string
+	}{
+		{
+			name:     "single type",
+			value:    "Data",
+			expected: []string{"Data"},
+		},
+		{
+			name:     "spaces around the names",
+			value:    "Data, MoreData ,\tOtherData",
+			expected: []string{"Data", "MoreData", "OtherData"},
+		},
+		{
+			name:           "empty name",
+			value:          "Data,,MoreData",
+			expectedErrMsg: `invalid -type "Data,,MoreData": type name 2 is empty`,
+		},
+		{
+			name:           "blank name",
+			value:          "Data, ",
+			expectedErrMsg: `invalid -type "Data, ": type name 2 is empty`,
+		},
+		{
+			name:           "duplicate name",
+			value:          "Data,MoreData, Data",
+			expectedErrMsg: `invalid -type "Data,MoreData, Data": type Data is listed more than once`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			names, err := parseTypeNames(test.value)
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(test.expectedErrMsg))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(names).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestResolveSetting(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		commandLineFlags map[string]struct{}
+		name             string
+		markerValue      string
+		expected         string
+	}{
+		{
+			name:     "flag",
+			expected: "gateway.nginx.org",
+		},
+		{
+			name:        "marker",
+			markerValue: "example.com",
+			expected:    "example.com",
+		},
+		{
+			name:             "flag set on the command line",
+			commandLineFlags: map[string]struct{}{"scheme-namespace": {}},
+			markerValue:      "example.com",
+			expected:         "gateway.nginx.org",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			value := resolveSetting(test.commandLineFlags, "scheme-namespace", test.markerValue)
+
+			g.Expect(value).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestGetTypeFileNames(t *testing.T) {
+	t.Parallel()
+
+	typeCfgs := map[string]typeConfig{
+		"Data": {
+			Name: "Data",
+			Scheme: typeSchemeConfig{
+				Output:           "schemes/data.avdl",
+				AvscOutput:       stdoutPath,
+				JSONSchemaOutput: "schemes/data.json",
+				ProtoOutput:      "schemes/data.proto",
+				DocsOutput:       "docs/data.md",
+			},
+		},
+	}
+
+	tests := []struct {
+		getFileName func(outputPath, typeName string) string
+		name        string
+		outputPath  string
+		typeName    string
+		expected    string
+	}{
+		{
+			name: "scheme default",
+			getFileName: func(outputPath, typeName string) string {
+				return getSchemeFileName(outputPath, typeName, typeCfgs)
+			},
+			typeName: "MoreData",
+			expected: "moredata.avdl",
+		},
+		{
+			name: "scheme config",
+			getFileName: func(outputPath, typeName string) string {
+				return getSchemeFileName(outputPath, typeName, typeCfgs)
+			},
+			typeName: "Data",
+			expected: "schemes/data.avdl",
+		},
+		{
+			name: "scheme flag overrides config",
+			getFileName: func(outputPath, typeName string) string {
+				return getSchemeFileName(outputPath, typeName, typeCfgs)
+			},
+			outputPath: stdoutPath,
+			typeName:   "Data",
+			expected:   stdoutPath,
+		},
+		{
+			name: "avsc config",
+			getFileName: func(outputPath, typeName string) string {
+				return getAvscFileName(outputPath, typeName, typeCfgs)
+			},
+			typeName: "Data",
+			expected: stdoutPath,
+		},
+		{
+			name: "avsc default",
+			getFileName: func(outputPath, typeName string) string {
+				return getAvscFileName(outputPath, typeName, typeCfgs)
+			},
+			typeName: "MoreData",
+			expected: "moredata.avsc",
+		},
+		{
+			name: "JSON Schema flag",
+			getFileName: func(outputPath, typeName string) string {
+				return getJSONSchemaFileName(outputPath, typeName, typeCfgs)
+			},
+			outputPath: "data.schema.json",
+			typeName:   "Data",
+			expected:   "data.schema.json",
+		},
+		{
+			name: "Protobuf schema default",
+			getFileName: func(outputPath, typeName string) string {
+				return getProtoFileName(outputPath, typeName, typeCfgs)
+			},
+			typeName: "MoreData",
+			expected: "moredata.proto",
+		},
+		{
+			name: "docs default in HTML",
+			getFileName: func(outputPath, typeName string) string {
+				return getDocsFileName(outputPath, typeName, docsFormatHTML, typeCfgs)
+			},
+			typeName: "MoreData",
+			expected: "moredata.html",
+		},
+		{
+			name: "docs config",
+			getFileName: func(outputPath, typeName string) string {
+				return getDocsFileName(outputPath, typeName, docsFormatHTML, typeCfgs)
+			},
+			typeName: "Data",
+			expected: "docs/data.md",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(test.getFileName(test.outputPath, test.typeName)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestIsStdoutUsed(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		typeCfgs    map[string]typeConfig
+		name        string
+		outputFlags []string
+		expected    bool
+	}{
+		{
+			name:     "no outputs",
+			expected: false,
+		},
+		{
+			name:        "files",
+			outputFlags: []string{"data.go", "", "data.avsc"},
+			typeCfgs: map[string]typeConfig{
+				"Data": {Name: "Data", Scheme: typeSchemeConfig{Output: "data.avdl"}},
+			},
+			expected: false,
+		},
+		{
+			name:        "output flag",
+			outputFlags: []string{"", stdoutPath},
+			expected:    true,
+		},
+		{
+			name: "scheme config",
+			typeCfgs: map[string]typeConfig{
+				"Data": {Name: "Data", Scheme: typeSchemeConfig{Output: stdoutPath}},
+			},
+			expected: true,
+		},
+		{
+			name: "docs config",
+			typeCfgs: map[string]typeConfig{
+				"Data":     {Name: "Data"},
+				"MoreData": {Name: "MoreData", Scheme: typeSchemeConfig{DocsOutput: stdoutPath}},
+			},
+			expected: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(isStdoutUsed(test.outputFlags, test.typeCfgs)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestRunLogsToStderrWhenWritingToStdout(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cmd := exec.Command(
+		"go", "run", "-tags", "generator", "github.com/nginx/telemetry-exporter/cmd/generator",
+		"-type=Data", "-output=-", "-scheme-output=-",
+	)
+	cmd.Dir = "tests"
+	cmd.Env = append(os.Environ(), "GOPACKAGE=tests")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	g.Expect(cmd.Run()).To(Succeed(), stderr.String())
+
+	// stdout only has the generated code and scheme, so that they can be piped
+	g.Expect(stdout.String()).To(HavePrefix("//go:build generator\n"))
+	g.Expect(stdout.String()).To(ContainSubstring(`@namespace("gateway.nginx.org") protocol NGFProductTelemetry {`))
+	g.Expect(stdout.String()).ToNot(ContainSubstring("Successfully parsed"))
+	g.Expect(stdout.String()).ToNot(ContainSubstring("Generating"))
+
+	g.Expect(stderr.String()).To(ContainSubstring("Successfully parsed struct Data"))
+	g.Expect(stderr.String()).To(ContainSubstring("Generating code"))
+	g.Expect(stderr.String()).To(ContainSubstring("Generating scheme of struct Data"))
+}
+
+func TestRunCompatWithGeneratedAvsc(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cmd := exec.Command(
+		"go", "run", "-tags", "generator", "github.com/nginx/telemetry-exporter/cmd/generator",
+		compatCommand, "-type=Data", "-compatibility=full",
+	)
+	cmd.Dir = "tests"
+	cmd.Env = append(os.Environ(), "GOPACKAGE=tests")
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	// the scheme of Data is compared with the generated data.avsc, which is up to date
+	g.Expect(cmd.Run()).To(Succeed(), stderr.String())
+	g.Expect(stdout.String()).To(ContainSubstring("Checking compatibility of scheme of struct Data with data.avsc"))
+	g.Expect(stdout.String()).To(ContainSubstring("Schemes are compatible"))
+}
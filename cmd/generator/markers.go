@@ -0,0 +1,240 @@
+//go:build generator
+
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// markerPrefix is the prefix of the marker comments that annotate the structs, like controller-gen markers.
+// A marker is a line of the doc comment of the struct in the form of +telemetry:<name> or +telemetry:<name>=<value>.
+const markerPrefix = "+telemetry:"
+
+const (
+	// markerGenerate marks a struct for generation when the generator discovers structs.
+	markerGenerate = "generate"
+	// markerSchemeProtocol sets the scheme protocol of the struct and enables the generation of its scheme.
+	markerSchemeProtocol = "scheme:protocol"
+	// markerSchemeNamespace sets the scheme namespace of the struct.
+	markerSchemeNamespace = "scheme:namespace"
+	// markerSchemeDataType sets the data fabric data type of the struct.
+	markerSchemeDataType = "scheme:datatype"
+	// markerBuildTags sets the comma separated list of build tags expected in the source files of the struct.
+	markerBuildTags = "build-tags"
+)
+
+// generateMarker is the comment that marks a struct for generation when the generator discovers structs.
+const generateMarker = markerPrefix + markerGenerate
+
+// typeMarkers are the settings of a struct set by its marker comments.
+// Empty values mean the settings are not set, so the flags apply.
+type typeMarkers struct {
+	schemeProtocol  string
+	schemeNamespace string
+	schemeDataType  string
+	buildTags       string
+	generate        bool
+}
+
+// parseTypeMarkers parses the marker comments of the doc comment of a struct.
+func parseTypeMarkers(doc *ast.CommentGroup) (typeMarkers, error) {
+	var markers typeMarkers
+
+	if doc == nil {
+		return markers, nil
+	}
+
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		line = strings.TrimSpace(line)
+
+		marker, ok := strings.CutPrefix(line, markerPrefix)
+		if !ok {
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(marker, "=")
+
+		if name == markerGenerate {
+			if hasValue {
+				return typeMarkers{}, fmt.Errorf("marker %s%s doesn't accept a value", markerPrefix, name)
+			}
+
+			markers.generate = true
+			continue
+		}
+
+		var target *string
+
+		switch name {
+		case markerSchemeProtocol:
+			target = &markers.schemeProtocol
+		case markerSchemeNamespace:
+			target = &markers.schemeNamespace
+		case markerSchemeDataType:
+			target = &markers.schemeDataType
+		case markerBuildTags:
+			target = &markers.buildTags
+		default:
+			return typeMarkers{}, fmt.Errorf("unknown marker %s%s", markerPrefix, name)
+		}
+
+		if value == "" {
+			return typeMarkers{}, fmt.Errorf("marker %s%s requires a value", markerPrefix, name)
+		}
+
+		*target = value
+	}
+
+	return markers, nil
+}
+
+// markedType is a struct with its markers.
+type markedType struct {
+	name    string
+	markers typeMarkers
+}
+
+// findTypeMarkers returns the markers of the structs declared in the files, in the order of their declaration.
+func findTypeMarkers(files []*ast.File) ([]markedType, error) {
+	var marked []markedType
+
+	for _, fileAst := range files {
+		for _, decl := range fileAst.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.TYPE {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				typeSpec, ok := spec.(*ast.TypeSpec)
+				if !ok {
+					continue
+				}
+
+				if _, isStruct := typeSpec.Type.(*ast.StructType); !isStruct {
+					continue
+				}
+
+				// the doc comment of an ungrouped type declaration belongs to the declaration
+				doc := typeSpec.Doc
+				if doc == nil && len(genDecl.Specs) == 1 {
+					doc = genDecl.Doc
+				}
+
+				markers, err := parseTypeMarkers(doc)
+				if err != nil {
+					return nil, fmt.Errorf("type %s: %w", typeSpec.Name.Name, err)
+				}
+
+				marked = append(marked, markedType{
+					name:    typeSpec.Name.Name,
+					markers: markers,
+				})
+			}
+		}
+	}
+
+	return marked, nil
+}
+
+// scanBuildTags returns the build tags set by the markers of the structs declared in the Go files of the package
+// in the directory. The files are parsed without loading the package, because the build tags are needed to load it,
+// so a file is in the package when it matches the build constraints with the build tags set by its own markers.
+// The structs must not set different build tags, because the generated code of a package is in a single file.
+func scanBuildTags(dir, pkgName string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read directory %s: %w", dir, err)
+	}
+
+	fset := token.NewFileSet()
+
+	var files []*ast.File
+
+	for _, entry := range entries {
+		name := entry.Name()
+
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+
+		fileAst, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return "", fmt.Errorf("failed to parse file %s: %w", name, err)
+		}
+
+		inPackage, err := isFileInPackage(dir, name, fileAst, pkgName)
+		if err != nil {
+			return "", err
+		}
+
+		if inPackage {
+			files = append(files, fileAst)
+		}
+	}
+
+	marked, err := findTypeMarkers(files)
+	if err != nil {
+		return "", err
+	}
+
+	var buildTags, owner string
+
+	for _, t := range marked {
+		if t.markers.buildTags == "" {
+			continue
+		}
+
+		if buildTags != "" && buildTags != t.markers.buildTags {
+			return "", fmt.Errorf(
+				"build tags %s of type %s conflict with build tags %s of type %s",
+				t.markers.buildTags,
+				t.name,
+				buildTags,
+				owner,
+			)
+		}
+
+		buildTags, owner = t.markers.buildTags, t.name
+	}
+
+	return buildTags, nil
+}
+
+// isFileInPackage returns true if the file declares the package and matches the build constraints, including its
+// name, with the build tags set by the markers of its structs.
+func isFileInPackage(dir, name string, fileAst *ast.File, pkgName string) (bool, error) {
+	if fileAst.Name.Name != pkgName {
+		return false, nil
+	}
+
+	marked, err := findTypeMarkers([]*ast.File{fileAst})
+	if err != nil {
+		return false, err
+	}
+
+	var tags []string
+
+	for _, t := range marked {
+		if t.markers.buildTags != "" {
+			tags = append(tags, strings.Split(t.markers.buildTags, ",")...)
+		}
+	}
+
+	ctx := build.Default
+	ctx.BuildTags = tags
+
+	match, err := ctx.MatchFile(dir, name)
+	if err != nil {
+		return false, fmt.Errorf("failed to match the build constraints of file %s: %w", name, err)
+	}
+
+	return match, nil
+}
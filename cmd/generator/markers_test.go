@@ -0,0 +1,198 @@
+//go:build generator
+
+package main
+
+import (
+	"go/ast"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func newCommentGroup(lines ...string) *ast.CommentGroup {
+	comments := make([]*ast.Comment, 0, len(lines))
+
+	for _, line := range lines {
+		comments = append(comments, &ast.Comment{Text: "// " + line})
+	}
+
+	return &ast.CommentGroup{List: comments}
+}
+
+func TestParseTypeMarkers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		expectedErrMsg string
+		doc            *ast.CommentGroup
+		expected       typeMarkers
+	}{
+		{
+			name:     "no doc",
+			doc:      nil,
+			expected: typeMarkers{},
+		},
+		{
+			name:     "no markers",
+			doc:      newCommentGroup("Data is data.", "It is not +telemetry:generate"),
+			expected: typeMarkers{},
+		},
+		{
+			name: "all markers",
+			doc: newCommentGroup(
+				"Data is data.",
+				"",
+				"+telemetry:generate",
+				"+telemetry:scheme:protocol=NGFProductTelemetry",
+				"+telemetry:scheme:namespace=gateway.nginx.org",
+				"+telemetry:scheme:datatype=ngf-product-telemetry",
+				"+telemetry:build-tags=generator,other",
+			),
+			expected: typeMarkers{
+				generate:        true,
+				schemeProtocol:  "NGFProductTelemetry",
+				schemeNamespace: "gateway.nginx.org",
+				schemeDataType:  "ngf-product-telemetry",
+				buildTags:       "generator,other",
+			},
+		},
+		{
+			name:           "unknown marker",
+			doc:            newCommentGroup("+telemetry:unknown"),
+			expectedErrMsg: "unknown marker +telemetry:unknown",
+		},
+		{
+			name:           "generate with value",
+			doc:            newCommentGroup("+telemetry:generate=true"),
+			expectedErrMsg: "marker +telemetry:generate doesn't accept a value",
+		},
+		{
+			name:           "missing value",
+			doc:            newCommentGroup("+telemetry:scheme:protocol"),
+			expectedErrMsg: "marker +telemetry:scheme:protocol requires a value",
+		},
+		{
+			name:           "empty value",
+			doc:            newCommentGroup("+telemetry:build-tags="),
+			expectedErrMsg: "marker +telemetry:build-tags requires a value",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			markers, err := parseTypeMarkers(test.doc)
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(test.expectedErrMsg))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(markers).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestScanBuildTags(t *testing.T) {
+	t.Parallel()
+
+	const (
+		taggedFile = `package tags
+
+// Data is data.
+//
+// +telemetry:build-tags=generator
+type Data struct{}
+`
+		otherTaggedFile = `package tags
+
+// OtherData is data.
+//
+// +telemetry:build-tags=other
+type OtherData struct{}
+`
+		untaggedFile = `package tags
+
+// UntaggedData is data.
+type UntaggedData struct{}
+`
+	)
+
+	tests := []struct {
+		files          map[string]string
+		name           string
+		expectedTags   string
+		expectedErrMsg string
+	}{
+		{
+			name:         "no build tags",
+			files:        map[string]string{"untagged.go": untaggedFile},
+			expectedTags: "",
+		},
+		{
+			name: "build tags",
+			files: map[string]string{
+				"tagged.go":   taggedFile,
+				"untagged.go": untaggedFile,
+				// test files are not scanned
+				"tagged_test.go": strings.ReplaceAll(otherTaggedFile, "package tags", "package tags_test"),
+			},
+			expectedTags: "generator",
+		},
+		{
+			name: "build constraints",
+			files: map[string]string{
+				"tagged.go": "//go:build generator\n\n" + taggedFile,
+				// the files excluded by their build constraints are not in the package
+				"ignored.go": "//go:build ignore\n\n" + otherTaggedFile,
+			},
+			expectedTags: "generator",
+		},
+		{
+			name: "other package",
+			files: map[string]string{
+				"tagged.go": taggedFile,
+				"tool.go":   strings.ReplaceAll(otherTaggedFile, "package tags", "package main"),
+			},
+			expectedTags: "generator",
+		},
+		{
+			name: "conflicting build tags",
+			files: map[string]string{
+				"other.go":  otherTaggedFile,
+				"tagged.go": taggedFile,
+			},
+			expectedErrMsg: "build tags generator of type Data conflict with build tags other of type OtherData",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			dir := t.TempDir()
+
+			for name, content := range test.files {
+				g.Expect(os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600)).To(Succeed())
+			}
+
+			tags, err := scanBuildTags(dir, "tags")
+
+			if test.expectedErrMsg != "" {
+				g.Expect(err).To(MatchError(test.expectedErrMsg))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tags).To(Equal(test.expectedTags))
+		})
+	}
+}
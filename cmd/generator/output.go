@@ -0,0 +1,204 @@
+//go:build generator
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// generatedOutput is the generated content of an output file.
+type generatedOutput struct {
+	// path is the path of the file or stdoutPath for stdout.
+	path    string
+	content []byte
+}
+
+// outputFileMode is the mode of new output files.
+const outputFileMode fs.FileMode = 0o644
+
+// writeOutputs writes the outputs to their files or stdout.
+// Regular files are replaced by temporary files written in their directories, and the file that a symlink links to
+// is replaced instead of the symlink. The files are replaced only after all temporary files are written, so if
+// writing a temporary file fails, the files are unchanged. The files are replaced one by one, so if replacing a file
+// fails, the files before it are already replaced. Files that are not regular files, like devices, and symlinks to
+// missing files are written in place.
+func writeOutputs(outputs []generatedOutput) error {
+	tempPaths := make(map[string]string, len(outputs))
+
+	err := replaceOutputs(outputs, tempPaths)
+
+	// the temporary files that replaced their files no longer exist
+	for _, tempPath := range tempPaths {
+		if removeErr := os.Remove(tempPath); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+			err = errors.Join(err, fmt.Errorf("failed to remove temporary file: %w", removeErr))
+		}
+	}
+
+	return err
+}
+
+// replaceOutputs writes the outputs to temporary files and replaces their files with them, saving the paths
+// of the temporary files by the paths of their outputs, so that they can be removed if replacing fails.
+func replaceOutputs(outputs []generatedOutput, tempPaths map[string]string) error {
+	replacedPaths := make(map[string]string, len(outputs))
+
+	for _, o := range outputs {
+		if o.path == stdoutPath {
+			continue
+		}
+
+		if _, exists := replacedPaths[o.path]; exists {
+			return fmt.Errorf("multiple outputs are written to file %s", o.path)
+		}
+
+		replacedPath, err := getReplacedPath(o.path)
+		if err != nil {
+			return err
+		}
+
+		replacedPaths[o.path] = replacedPath
+
+		if replacedPath == "" {
+			continue
+		}
+
+		tempPath, err := writeTempFile(replacedPath, o.content)
+		if tempPath != "" {
+			tempPaths[o.path] = tempPath
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, o := range outputs {
+		if err := replaceOutput(o, replacedPaths[o.path], tempPaths[o.path]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// replaceOutput writes the output to stdout, replaces the file with the temporary file, or, if there is no file to
+// replace, writes the output in place.
+func replaceOutput(o generatedOutput, replacedPath, tempPath string) error {
+	switch {
+	case o.path == stdoutPath:
+		if _, err := os.Stdout.Write(o.content); err != nil {
+			return fmt.Errorf("failed to write to stdout: %w", err)
+		}
+	case replacedPath == "":
+		//nolint:gosec // the mode only applies to new files, which are readable like the other generated files
+		if err := os.WriteFile(o.path, o.content, outputFileMode); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+	default:
+		if err := os.Rename(tempPath, replacedPath); err != nil {
+			return fmt.Errorf("failed to replace file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// getReplacedPath returns the path of the file that is replaced by the output: the path of the output, if it is
+// a regular file or missing, or the path of the regular file that it links to, if it is a symlink.
+// It returns an empty path if the output must be written in place, because it is neither.
+func getReplacedPath(path string) (string, error) {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return path, nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get info of file: %w", err)
+	}
+
+	if info.Mode().IsRegular() {
+		return path, nil
+	}
+
+	if info.Mode()&fs.ModeSymlink == 0 {
+		return "", nil
+	}
+
+	resolvedPath, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		// writing through the symlink creates the file that it links to
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve symlink: %w", err)
+	}
+
+	// the resolved path is not a symlink
+	return getReplacedPath(resolvedPath)
+}
+
+// writeTempFile writes the content to a temporary file in the directory of the file at the path, with the mode of
+// the file if it exists. It returns the path of the temporary file, if it was created, even if writing fails.
+func writeTempFile(path string, content []byte) (string, error) {
+	mode := outputFileMode
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return file.Name(), fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := file.Chmod(mode); err != nil {
+		_ = file.Close()
+		return file.Name(), fmt.Errorf("failed to set mode of temporary file: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return file.Name(), fmt.Errorf("failed to close temporary file: %w", err)
+	}
+
+	return file.Name(), nil
+}
+
+// checkOutputs compares the outputs with their files, writing the unified diffs of the files that differ to the writer.
+// It returns the paths of the files that differ. Missing files differ from any output.
+func checkOutputs(writer io.Writer, outputs []generatedOutput) ([]string, error) {
+	var outdated []string
+
+	for _, o := range outputs {
+		if o.path == stdoutPath {
+			return nil, errors.New("checking an output written to stdout is not supported")
+		}
+
+		content, err := os.ReadFile(o.path) //nolint:gosec // the path of the output is set by the user
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read file: %w", err)
+		}
+
+		diff := unifiedDiff(o.path, o.path+" (generated)", string(content), string(o.content))
+		if diff == "" {
+			continue
+		}
+
+		if _, err := io.WriteString(writer, diff); err != nil {
+			return nil, fmt.Errorf("failed to write diff: %w", err)
+		}
+
+		outdated = append(outdated, o.path)
+	}
+
+	return outdated, nil
+}
@@ -0,0 +1,200 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestWriteAndCheckOutputs(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+
+	outputs := []generatedOutput{
+		{path: filepath.Join(dir, "code.go"), content: []byte("package code\n")},
+		{path: filepath.Join(dir, "scheme.avdl"), content: []byte("protocol Scheme {}\n")},
+	}
+
+	var diffs bytes.Buffer
+
+	outdated, err := checkOutputs(&diffs, outputs)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(outdated).To(Equal([]string{outputs[0].path, outputs[1].path}))
+	g.Expect(diffs.String()).To(ContainSubstring("+package code\n"))
+
+	g.Expect(writeOutputs(outputs)).To(Succeed())
+
+	diffs.Reset()
+
+	outdated, err = checkOutputs(&diffs, outputs)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(outdated).To(BeEmpty())
+	g.Expect(diffs.String()).To(BeEmpty())
+
+	g.Expect(os.WriteFile(outputs[1].path, []byte("protocol Old {}\n"), 0o600)).To(Succeed())
+
+	outdated, err = checkOutputs(&diffs, outputs)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(outdated).To(Equal([]string{outputs[1].path}))
+	g.Expect(diffs.String()).To(ContainSubstring("-protocol Old {}\n+protocol Scheme {}\n"))
+
+	_, err = checkOutputs(&diffs, []generatedOutput{{path: stdoutPath}})
+
+	g.Expect(err).To(MatchError("checking an output written to stdout is not supported"))
+}
+
+func TestWriteOutputsFailure(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+
+	codePath := filepath.Join(dir, "code.go")
+
+	g.Expect(os.WriteFile(codePath, []byte("package old\n"), 0o600)).To(Succeed())
+
+	tests := []struct {
+		name           string
+		expectedErrMsg string
+		outputs        []generatedOutput
+	}{
+		{
+			name: "missing directory",
+			outputs: []generatedOutput{
+				{path: codePath, content: []byte("package code\n")},
+				{path: filepath.Join(dir, "missing", "scheme.avdl"), content: []byte("protocol Scheme {}\n")},
+			},
+			expectedErrMsg: "failed to create temporary file",
+		},
+		{
+			name: "duplicate paths",
+			outputs: []generatedOutput{
+				{path: codePath, content: []byte("package code\n")},
+				{path: codePath, content: []byte("protocol Scheme {}\n")},
+			},
+			expectedErrMsg: "multiple outputs are written to file " + codePath,
+		},
+	}
+
+	for _, test := range tests {
+		g.Expect(writeOutputs(test.outputs)).To(MatchError(ContainSubstring(test.expectedErrMsg)), test.name)
+
+		content, err := os.ReadFile(codePath)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal("package old\n"), test.name)
+
+		entries, err := os.ReadDir(dir)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(entries).To(HaveLen(1), "temporary files must be removed")
+	}
+}
+
+func TestWriteOutputsKeepsFileMode(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+
+	existingPath := filepath.Join(dir, "existing.go")
+	newPath := filepath.Join(dir, "new.go")
+
+	g.Expect(os.WriteFile(existingPath, []byte("package old\n"), 0o600)).To(Succeed())
+
+	outputs := []generatedOutput{
+		{path: existingPath, content: []byte("package existing\n")},
+		{path: newPath, content: []byte("package new\n")},
+	}
+
+	g.Expect(writeOutputs(outputs)).To(Succeed())
+
+	info, err := os.Stat(existingPath)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+	info, err = os.Stat(newPath)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(outputFileMode))
+}
+
+func TestWriteOutputsSymlinks(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+
+	realPath := filepath.Join(dir, "real.go")
+	linkPath := filepath.Join(dir, "link.go")
+	missingPath := filepath.Join(dir, "missing.go")
+	danglingLinkPath := filepath.Join(dir, "dangling.go")
+
+	g.Expect(os.WriteFile(realPath, []byte("package old\n"), 0o600)).To(Succeed())
+	g.Expect(os.Symlink("real.go", linkPath)).To(Succeed())
+	g.Expect(os.Symlink("missing.go", danglingLinkPath)).To(Succeed())
+
+	outputs := []generatedOutput{
+		{path: linkPath, content: []byte("package link\n")},
+		{path: danglingLinkPath, content: []byte("package dangling\n")},
+	}
+
+	g.Expect(writeOutputs(outputs)).To(Succeed())
+
+	// the symlinks are kept and the files they link to are written
+	for _, path := range []string{linkPath, danglingLinkPath} {
+		info, err := os.Lstat(path)
+
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(info.Mode()&os.ModeSymlink).ToNot(BeZero(), path)
+	}
+
+	content, err := os.ReadFile(realPath)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(content)).To(Equal("package link\n"))
+
+	info, err := os.Stat(realPath)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0o600)))
+
+	content, err = os.ReadFile(missingPath)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(content)).To(Equal("package dangling\n"))
+
+	entries, err := os.ReadDir(dir)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(entries).To(HaveLen(4), "temporary files must be removed")
+}
+
+func TestWriteOutputsDevice(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	info, err := os.Stat(os.DevNull)
+	if err != nil || info.Mode()&os.ModeDevice == 0 {
+		t.Skip("no null device")
+	}
+
+	g.Expect(writeOutputs([]generatedOutput{{path: os.DevNull, content: []byte("package null\n")}})).To(Succeed())
+
+	// the device is written in place rather than replaced by a regular file
+	info, err = os.Stat(os.DevNull)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(info.Mode() & os.ModeDevice).ToNot(BeZero())
+}
@@ -6,8 +6,13 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/constant"
 	"go/token"
 	"go/types"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
 	"sort"
 	"strings"
 
@@ -18,8 +23,10 @@ import (
 type docStringFieldsProvider struct {
 	packages   map[string]struct{}
 	docStrings map[string]string
-	buildFlags []string
-	loadTests  bool
+	// typeDocStrings are the doc string comments of the structs, keyed by their full type names.
+	typeDocStrings map[string]string
+	buildFlags     []string
+	loadTests      bool
 }
 
 // newDocStringFieldsProvider creates a new docStringFieldsProvider.
@@ -27,10 +34,11 @@ type docStringFieldsProvider struct {
 // buildFlags are go build flags (e.g. -tags=foo).
 func newDocStringFieldsProvider(loadTests bool, buildFlags []string) *docStringFieldsProvider {
 	return &docStringFieldsProvider{
-		loadTests:  loadTests,
-		buildFlags: buildFlags,
-		packages:   make(map[string]struct{}),
-		docStrings: make(map[string]string),
+		loadTests:      loadTests,
+		buildFlags:     buildFlags,
+		packages:       make(map[string]struct{}),
+		docStrings:     make(map[string]string),
+		typeDocStrings: make(map[string]string),
 	}
 }
 
@@ -47,24 +55,39 @@ func getDocStringKey(pkgName, typeName, fieldName string) string {
 	return fmt.Sprintf("%s.%s.%s", pkgName, typeName, fieldName)
 }
 
-// getDocString returns the doc string comment for the field of the struct.
+// getFieldComment returns the comment of the field of the struct, and false if the field has no comment.
 // fullTypeName is the full type name of the struct
 // (e.g. "github.com/nginx/nginx-gateway-fabric/pkg/mypackage.MyStruct").
-func (p *docStringFieldsProvider) getDocString(fullTypeName, fieldName string) (string, error) {
+func (p *docStringFieldsProvider) getFieldComment(fullTypeName, fieldName string) (string, bool, error) {
 	pkgName, typeName := parseFullTypeName(fullTypeName)
 
 	_, exists := p.packages[pkgName]
 	if !exists {
 		if err := p.parseDocStringsFromPackage(pkgName); err != nil {
-			return "", fmt.Errorf("failed to load struct comments from package %s: %w", pkgName, err)
+			return "", false, fmt.Errorf("failed to load struct comments from package %s: %w", pkgName, err)
 		}
 	}
 
-	doc, exists := p.docStrings[getDocStringKey(pkgName, typeName, fieldName)]
+	comment, exists := p.docStrings[getDocStringKey(pkgName, typeName, fieldName)]
+
+	return comment, exists, nil
+}
+
+// getDocString returns the doc string comment for the field of the struct, without its marker comments.
+// fullTypeName is the full type name of the struct
+// (e.g. "github.com/nginx/nginx-gateway-fabric/pkg/mypackage.MyStruct").
+func (p *docStringFieldsProvider) getDocString(fullTypeName, fieldName string) (string, error) {
+	comment, exists, err := p.getFieldComment(fullTypeName, fieldName)
+	if err != nil {
+		return "", err
+	}
+
 	if !exists {
 		return "", errors.New("doc string not found")
 	}
 
+	doc, _ := splitFieldMarkers(comment)
+
 	trimmedComment := strings.TrimSpace(doc)
 	if trimmedComment == "" {
 		return "", errors.New("trimmed doc string is empty")
@@ -73,6 +96,36 @@ func (p *docStringFieldsProvider) getDocString(fullTypeName, fieldName string) (
 	return trimmedComment, nil
 }
 
+// getFieldMarkers returns the marker comments of the field of the struct, without the marker prefix.
+func (p *docStringFieldsProvider) getFieldMarkers(fullTypeName, fieldName string) ([]string, error) {
+	comment, _, err := p.getFieldComment(fullTypeName, fieldName)
+	if err != nil {
+		return nil, err
+	}
+
+	_, markers := splitFieldMarkers(comment)
+
+	return markers, nil
+}
+
+// getTypeDocString returns the doc string comment of the struct, without its marker comments, or an empty string
+// if the struct has no doc string.
+// fullTypeName is the full type name of the struct
+// (e.g. "github.com/nginx/nginx-gateway-fabric/pkg/mypackage.MyStruct").
+func (p *docStringFieldsProvider) getTypeDocString(fullTypeName string) (string, error) {
+	pkgName, _ := parseFullTypeName(fullTypeName)
+
+	if _, exists := p.packages[pkgName]; !exists {
+		if err := p.parseDocStringsFromPackage(pkgName); err != nil {
+			return "", fmt.Errorf("failed to load struct comments from package %s: %w", pkgName, err)
+		}
+	}
+
+	doc, _ := splitFieldMarkers(p.typeDocStrings[fullTypeName])
+
+	return strings.TrimSpace(doc), nil
+}
+
 func (p *docStringFieldsProvider) parseDocStringsFromPackage(pkgName string) error {
 	mode := packages.NeedName | packages.NeedSyntax | packages.NeedTypes
 
@@ -105,12 +158,25 @@ func (p *docStringFieldsProvider) parseDocStringsFromPackage(pkgName string) err
 		return fmt.Errorf("package %s not found", pkgName)
 	}
 
-	p.packages[pkgName] = struct{}{}
+	p.addPackage(loadedPkg)
+
+	return nil
+}
+
+// addPackage saves the doc string comments for the fields of the structs of the package, which must be loaded with
+// syntax.
+func (p *docStringFieldsProvider) addPackage(loadedPkg *packages.Package) {
+	p.packages[loadedPkg.PkgPath] = struct{}{}
 
 	// for each struct in the package,
-	// save the doc string comments for the fields of the struct
+	// save the doc string comments for the struct and its fields
 	for _, fileAst := range loadedPkg.Syntax {
 		ast.Inspect(fileAst, func(n ast.Node) bool {
+			if genDecl, ok := n.(*ast.GenDecl); ok {
+				p.addTypeDocStrings(loadedPkg.PkgPath, genDecl)
+				return true
+			}
+
 			structTypeSpec, ok := n.(*ast.TypeSpec)
 			if !ok {
 				return true
@@ -134,8 +200,30 @@ func (p *docStringFieldsProvider) parseDocStringsFromPackage(pkgName string) err
 			return true
 		})
 	}
+}
 
-	return nil
+// addTypeDocStrings saves the doc string comments for the types declared by the declaration.
+func (p *docStringFieldsProvider) addTypeDocStrings(pkgPath string, genDecl *ast.GenDecl) {
+	if genDecl.Tok != token.TYPE {
+		return
+	}
+
+	for _, spec := range genDecl.Specs {
+		typeSpec, ok := spec.(*ast.TypeSpec)
+		if !ok {
+			continue
+		}
+
+		// the doc comment of an ungrouped type declaration belongs to the declaration
+		doc := typeSpec.Doc
+		if doc == nil && len(genDecl.Specs) == 1 {
+			doc = genDecl.Doc
+		}
+
+		if comment := doc.Text(); comment != "" {
+			p.typeDocStrings[pkgPath+"."+typeSpec.Name.Name] = comment
+		}
+	}
 }
 
 type parsingError struct {
@@ -150,48 +238,224 @@ func (e parsingError) Error() string {
 
 // parsingConfig is a configuration for the parser.
 type parsingConfig struct {
-	// pkgName is the name of the package where the struct is located.
+	// pkgName is the name of the package where the structs are located.
 	pkgName string
-	// typeName is the name of the struct.
-	typeName string
 	// loadPattern is the pattern to load the package.
 	// For example, "github.com/nginx/nginx-gateway-fabric/pkg/mypackage" or "."
 	// The path in the pattern is relative to the current working directory.
 	loadPattern string
+	// timePrecision is the precision of time.Time and time.Duration fields. Defaults to milliseconds.
+	timePrecision timePrecision
+	// typeNames are the names of the structs.
+	typeNames []string
 	// buildFlags are go build flags (e.g. -tags=foo).
 	buildFlags []string
 	// loadTests specifies whether the parser will load test files (e.g. *_test.go).
 	loadTests bool
+	// discover specifies whether the parser will also parse all structs of the package annotated with
+	// the generateMarker comment.
+	discover bool
 }
 
 // parsingResult is the result of the parsing.
 type parsingResult struct {
-	// packagePath is the package path of the parsed struct.
+	// packagePath is the package path of the parsed structs.
 	packagePath string
-	// fields are the fields of the parsed struct, including the fields of the embedded structs.
+	// types are the parsed structs, in the order of parsingConfig.typeNames, followed by the discovered structs
+	// in the order of their declaration.
+	types []parsedType
+}
+
+// parsedType is a parsed struct.
+type parsedType struct {
+	// name is the name of the struct.
+	name string
+	// fields are the fields of the struct, including the fields of the embedded structs.
 	fields []field
+	// markers are the settings of the struct set by its marker comments.
+	markers typeMarkers
 }
 
 // field represents a field of a struct.
-// the field is either a basic type, a slice of basic type or an embedded struct.
+// the field is either a basic type, a pointer to basic type, a slice of basic type, a map of string to basic type
+// or an embedded struct.
 type field struct {
-	docString            string
-	name                 string
-	embeddedStructFields []field
-	fieldType            types.BasicKind
-	slice                bool
-	embeddedStruct       bool
+	// named is set when the type of the field value (the field, slice element or map value) is a named type.
+	named     *namedType
+	docString string
+	name      string
+	// key is the key of the attribute when it is different from the name of the field.
+	key string
+	// timePrecision is the precision of the field value when timeKind is set.
+	timePrecision timePrecision
+	// embeddedStructPackage is the path of the package of the embedded struct, which is named like the field.
+	embeddedStructPackage string
+	// embeddedStructDocString is the doc string comment of the embedded struct, without its marker comments.
+	embeddedStructDocString string
+	embeddedStructFields    []field
+	// validation are the validation rules of the field.
+	validation fieldValidation
+	fieldType  types.BasicKind
+	// timeKind is set when the type of the field value is time.Time or time.Duration. Such values are encoded as int64
+	// in timePrecision units: timestamps since the Unix epoch and durations.
+	timeKind timeKind
+	slice    bool
+	// stringMap is true when the field is a map with string keys. fieldType is the type of the map values.
+	stringMap bool
+	// pointer is true when the field is a pointer to fieldType. A nil pointer means the value is unknown (null).
+	pointer bool
+	// omitEmpty is true when the attribute is omitted for the zero value of the field.
+	omitEmpty      bool
+	embeddedStruct bool
 }
 
-// parse parses the struct defined by the config.
-// The fields of the struct must satisfy the following rules:
+// attributeKey returns the key of the attribute of the field, which is the name of the field, unless overridden.
+func (f field) attributeKey() string {
+	if f.key != "" {
+		return f.key
+	}
+
+	return f.name
+}
+
+// embeddedStructName returns the name of the embedded struct qualified by the name of its package,
+// like subtests.AnotherData.
+func (f field) embeddedStructName() string {
+	return path.Base(f.embeddedStructPackage) + "." + f.name
+}
+
+// fieldTag is the parsed telemetry struct tag of a field.
+// The tag has the format `telemetry:"key,omitempty,doc=Description."`, where all parts are optional, and doc must be
+// the last option, so that it can include commas. `telemetry:"-"` skips the field.
+// The validation rules are also options, like `telemetry:",required,maxItems=100"`.
+type fieldTag struct {
+	// key overrides the attribute key.
+	key string
+	// doc overrides the doc string of the field.
+	doc string
+	// validation are the validation rules of the field.
+	validation fieldValidation
+	// skip means the field is skipped.
+	skip bool
+	// omitEmpty means the attribute is omitted when the field has the zero value.
+	omitEmpty bool
+}
+
+func parseFieldTag(structTag string) (fieldTag, error) {
+	value, exists := reflect.StructTag(structTag).Lookup("telemetry")
+	if !exists {
+		return fieldTag{}, nil
+	}
+
+	if value == "-" {
+		return fieldTag{skip: true}, nil
+	}
+
+	var tag fieldTag
+
+	key, options, _ := strings.Cut(value, ",")
+
+	if key != "" && !attributeKeyRegexp.MatchString(key) {
+		return fieldTag{}, fmt.Errorf("telemetry tag key %q must match %s", key, attributeKeyRegexp.String())
+	}
+
+	tag.key = key
+
+	for options != "" {
+		if doc, isDoc := strings.CutPrefix(options, "doc="); isDoc {
+			tag.doc = strings.TrimSpace(doc)
+			if tag.doc == "" {
+				return fieldTag{}, errors.New("telemetry tag doc is empty")
+			}
+
+			break
+		}
+
+		var option string
+		option, options, _ = strings.Cut(options, ",")
+
+		if option == "omitempty" {
+			tag.omitEmpty = true
+			continue
+		}
+
+		name, ruleValue, hasValue := strings.Cut(option, "=")
+
+		found, err := tag.validation.applyValidationRule(name, ruleValue, hasValue)
+		if err != nil {
+			return fieldTag{}, fmt.Errorf("telemetry tag option %q: %w", option, err)
+		}
+
+		if !found {
+			return fieldTag{}, fmt.Errorf("unknown telemetry tag option %q", option)
+		}
+	}
+
+	return tag, nil
+}
+
+// attributeKeyRegexp matches the attribute keys that can be set in the telemetry tag.
+// Unlike Avro names, they may include dots, which are replaced with underscores in the scheme.
+var attributeKeyRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// avroNameRegexp matches valid Avro names.
+var avroNameRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// timeKind is the kind of time value of a field.
+type timeKind int
+
+const (
+	// timeKindNone means the field value is not a time value.
+	timeKindNone timeKind = iota
+	// timeKindTimestamp means the field value is time.Time.
+	timeKindTimestamp
+	// timeKindDuration means the field value is time.Duration.
+	timeKindDuration
+)
+
+// timePrecision is the precision with which time values are encoded.
+type timePrecision string
+
+const (
+	timePrecisionSeconds      timePrecision = "s"
+	timePrecisionMilliseconds timePrecision = "ms"
+	timePrecisionMicroseconds timePrecision = "us"
+	timePrecisionNanoseconds  timePrecision = "ns"
+)
+
+// timePrecisions are the supported time precisions.
+var timePrecisions = []timePrecision{
+	timePrecisionSeconds,
+	timePrecisionMilliseconds,
+	timePrecisionMicroseconds,
+	timePrecisionNanoseconds,
+}
+
+// namedType is a named type of a field value, like `type Platform string`, or a type that implements fmt.Stringer.
+type namedType struct {
+	// packagePath is the package path of the type.
+	packagePath string
+	// name is the name of the type.
+	name string
+	// enumValues are the sorted values of the typed string constants of the type, declared in its package.
+	enumValues []string
+	// stringer is true when the type implements fmt.Stringer. Such values are exported using the String method.
+	stringer bool
+}
+
+// parse parses the structs defined by the config. The package of the structs is loaded only once.
+// The fields of the structs must satisfy the following rules:
 // - Must be exported.
-// - Must be of basic type, slice of basic type or embedded struct, where the embedded struct must satisfy the same
-// rules.
-// - Must have unique names across all embedded structs.
-// - Must have a doc string comment for each field.
+// - Must be of basic type, pointer to basic type, slice of basic type, map of string to basic type or embedded struct,
+// where the embedded struct must satisfy the same rules. Named types with such underlying types, time.Time,
+// time.Duration and types that implement fmt.Stringer are basic types too.
+// - Must have unique attribute keys across all embedded structs.
+// - Must not have attribute keys that start with the key of a map field followed by a dot, like Counts.x for
+// a map field Counts, because the attributes of the map are keyed like that.
+// - Must have a doc string comment for each field, unless it is set in the telemetry tag.
+// Fields with the `telemetry:"-"` tag are skipped.
 func parse(parsingCfg parsingConfig) (parsingResult, error) {
-	mode := packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo
+	mode := packages.NeedName | packages.NeedTypes | packages.NeedTypesInfo | packages.NeedSyntax
 
 	cfg := packages.Config{
 		Mode:       mode,
@@ -210,49 +474,105 @@ func parse(parsingCfg parsingConfig) (parsingResult, error) {
 		return parsingResult{}, fmt.Errorf("failed to load package: %w", err)
 	}
 
-	var pkg *packages.Package
-
-	for _, p := range loadedPackages {
-		if cfg.Tests && !strings.HasSuffix(p.ID, ".test]") {
-			continue
-		}
+	pkg := selectPackage(loadedPackages, parsingCfg.pkgName, cfg.Tests)
+	if pkg == nil {
+		return parsingResult{}, fmt.Errorf("package %s not found", parsingCfg.pkgName)
+	}
 
-		if p.Name == parsingCfg.pkgName {
-			pkg = p
-			break
-		}
+	marked, err := findTypeMarkers(pkg.Syntax)
+	if err != nil {
+		return parsingResult{}, err
 	}
 
-	if pkg == nil {
-		return parsingResult{}, fmt.Errorf("package %s not found", parsingCfg.pkgName)
+	markers := make(map[string]typeMarkers, len(marked))
+	for _, t := range marked {
+		markers[t.name] = t.markers
 	}
 
-	targetType := pkg.Types.Scope().Lookup(parsingCfg.typeName)
-	if targetType == nil {
-		return parsingResult{}, fmt.Errorf("type %s not found", parsingCfg.typeName)
+	typeNames := parsingCfg.typeNames
+	if parsingCfg.discover {
+		typeNames = appendDiscoveredTypes(typeNames, marked)
 	}
 
-	s, ok := targetType.Type().Underlying().(*types.Struct)
-	if !ok {
-		return parsingResult{}, fmt.Errorf("expected struct, got %s", targetType.Type().Underlying().String())
+	if len(typeNames) == 0 {
+		return parsingResult{}, errors.New("no types to parse")
 	}
 
+	// the package is already loaded, so the doc strings of its structs don't require loading it again
 	docStringProvider := newDocStringFieldsProvider(parsingCfg.loadTests, parsingCfg.buildFlags)
+	docStringProvider.addPackage(pkg)
 
-	fields, err := parseStruct(s, targetType.Type().String(), docStringProvider)
-	if err != nil {
-		return parsingResult{}, err
+	precision := parsingCfg.timePrecision
+	if precision == "" {
+		precision = timePrecisionMilliseconds
+	}
+
+	parsedTypes := make([]parsedType, 0, len(typeNames))
+
+	for _, typeName := range typeNames {
+		targetType := pkg.Types.Scope().Lookup(typeName)
+		if targetType == nil {
+			return parsingResult{}, fmt.Errorf("type %s not found", typeName)
+		}
+
+		s, ok := targetType.Type().Underlying().(*types.Struct)
+		if !ok {
+			return parsingResult{}, fmt.Errorf("expected struct, got %s", targetType.Type().Underlying().String())
+		}
+
+		fields, err := parseStruct(s, targetType.Type().String(), docStringProvider, precision)
+		if err != nil {
+			return parsingResult{}, err
+		}
+
+		parsedTypes = append(parsedTypes, parsedType{
+			name:    typeName,
+			fields:  fields,
+			markers: markers[typeName],
+		})
 	}
 
 	return parsingResult{
 		packagePath: pkg.PkgPath,
-		fields:      fields,
+		types:       parsedTypes,
 	}, nil
 }
 
+// selectPackage returns the package with the name among the loaded packages, or nil if it isn't found.
+// When the tests are loaded, the package is its test variant.
+func selectPackage(loadedPackages []*packages.Package, pkgName string, tests bool) *packages.Package {
+	for _, p := range loadedPackages {
+		if tests && !strings.HasSuffix(p.ID, ".test]") {
+			continue
+		}
+
+		if p.Name == pkgName {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// appendDiscoveredTypes appends the types marked for generation that are not in the type names yet.
+func appendDiscoveredTypes(typeNames []string, marked []markedType) []string {
+	for _, t := range marked {
+		if t.markers.generate && !slices.Contains(typeNames, t.name) {
+			typeNames = append(typeNames, t.name)
+		}
+	}
+
+	return typeNames
+}
+
 //nolint:gocyclo
-func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringFieldsProvider) ([]field, error) {
-	nameOwners := make(map[string]string)
+func parseStruct(
+	s *types.Struct,
+	typeName string,
+	docStringProvider *docStringFieldsProvider,
+	precision timePrecision,
+) ([]field, error) {
+	keyOwners := make(map[string]string)
 
 	var parseRecursively func(*types.Struct, string) ([]field, error)
 
@@ -291,14 +611,25 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 			}
 		}
 
+		docString, err := docStringProvider.getTypeDocString(t.String())
+		if err != nil {
+			return field{}, parsingError{
+				typeName:  typeName,
+				fieldName: f.Name(),
+				msg:       err.Error(),
+			}
+		}
+
 		return field{
-			name:                 f.Name(),
-			embeddedStruct:       true,
-			embeddedStructFields: embeddedFields,
+			name:                    f.Name(),
+			embeddedStruct:          true,
+			embeddedStructFields:    embeddedFields,
+			embeddedStructPackage:   t.Obj().Pkg().Path(),
+			embeddedStructDocString: docString,
 		}, nil
 	}
 
-	parseBasicTypeField := func(t *types.Basic, f *types.Var, typeName string) (field, error) {
+	parseBasicTypeField := func(t types.Type, f *types.Var, typeName string) (field, error) {
 		if f.Embedded() {
 			return field{}, parsingError{
 				typeName:  typeName,
@@ -313,15 +644,35 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 				msg:       "must be exported",
 			}
 		}
-		if _, allowed := allowedBasicKinds[t.Kind()]; !allowed {
+
+		vt, err := parseValueType(t, "field", f.Type())
+		if err != nil {
+			return field{}, parsingError{
+				typeName:  typeName,
+				fieldName: f.Name(),
+				msg:       err.Error(),
+			}
+		}
+
+		return field{
+			name:      f.Name(),
+			fieldType: vt.kind,
+			named:     vt.named,
+			timeKind:  vt.timeKind,
+		}, nil
+	}
+
+	parseSliceField := func(t *types.Slice, f *types.Var, typeName string) (field, error) {
+		// slices can't be embedded so we don't check for that here
+		if !f.Exported() {
 			return field{}, parsingError{
 				typeName:  typeName,
 				fieldName: f.Name(),
-				msg:       fmt.Sprintf("type of field must be one of %s, got %s", supportedKinds, f.Type().String()),
+				msg:       "must be exported",
 			}
 		}
 
-		comment, err := docStringProvider.getDocString(typeName, f.Name())
+		vt, err := parseValueType(t.Elem(), "field", f.Type())
 		if err != nil {
 			return field{}, parsingError{
 				typeName:  typeName,
@@ -332,13 +683,15 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 
 		return field{
 			name:      f.Name(),
-			fieldType: t.Kind(),
-			docString: comment,
+			fieldType: vt.kind,
+			named:     vt.named,
+			timeKind:  vt.timeKind,
+			slice:     true,
 		}, nil
 	}
 
-	parseSliceField := func(t *types.Slice, f *types.Var, typeName string) (field, error) {
-		// slices can't be embedded so we don't check for that here
+	parseMapField := func(t *types.Map, f *types.Var, typeName string) (field, error) {
+		// maps can't be embedded so we don't check for that here
 		if !f.Exported() {
 			return field{}, parsingError{
 				typeName:  typeName,
@@ -347,24 +700,50 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 			}
 		}
 
-		elemType, ok := t.Elem().(*types.Basic)
-		if !ok {
+		keyType, ok := t.Key().(*types.Basic)
+		if !ok || keyType.Kind() != types.String {
 			return field{}, parsingError{
 				typeName:  typeName,
 				fieldName: f.Name(),
-				msg:       fmt.Sprintf("type of field must be one of %s, got %s", supportedKinds, f.Type().String()),
+				msg:       "map key must be string, got " + t.Key().String(),
 			}
 		}
 
-		if _, allowed := allowedBasicKinds[elemType.Kind()]; !allowed {
+		vt, err := parseValueType(t.Elem(), "map value", f.Type())
+		if err != nil {
 			return field{}, parsingError{
 				typeName:  typeName,
 				fieldName: f.Name(),
-				msg:       fmt.Sprintf("type of field must be one of %s, got %s", supportedKinds, f.Type().String()),
+				msg:       err.Error(),
 			}
 		}
 
-		comment, err := docStringProvider.getDocString(typeName, f.Name())
+		return field{
+			name:      f.Name(),
+			fieldType: vt.kind,
+			named:     vt.named,
+			timeKind:  vt.timeKind,
+			stringMap: true,
+		}, nil
+	}
+
+	parsePointerField := func(t *types.Pointer, f *types.Var, typeName string) (field, error) {
+		if f.Embedded() {
+			return field{}, parsingError{
+				typeName:  typeName,
+				fieldName: f.Name(),
+				msg:       "embedded pointers are not allowed",
+			}
+		}
+		if !f.Exported() {
+			return field{}, parsingError{
+				typeName:  typeName,
+				fieldName: f.Name(),
+				msg:       "must be exported",
+			}
+		}
+
+		vt, err := parseValueType(t.Elem(), "field", f.Type())
 		if err != nil {
 			return field{}, parsingError{
 				typeName:  typeName,
@@ -375,33 +754,70 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 
 		return field{
 			name:      f.Name(),
-			fieldType: elemType.Kind(),
-			slice:     true,
-			docString: comment,
+			fieldType: vt.kind,
+			named:     vt.named,
+			timeKind:  vt.timeKind,
+			pointer:   true,
 		}, nil
 	}
 
+	parseNamedField := func(t *types.Named, f *types.Var, typeName string) (field, error) {
+		_, isStruct := t.Underlying().(*types.Struct)
+
+		switch u := t.Underlying().(type) {
+		case *types.Slice: // when the field is a named slice type like `type Names []string`.
+			return parseSliceField(u, f, typeName)
+		case *types.Map: // when the field is a named map type like `type Counts map[string]int64`.
+			return parseMapField(u, f, typeName)
+		}
+
+		// when the field is an embedded struct, or a struct that doesn't implement fmt.Stringer
+		if f.Embedded() || (isStruct && !types.Implements(t, stringerType)) {
+			return parseStructField(t, f, typeName)
+		}
+
+		// when the field is a named basic type like `type Platform string` or implements fmt.Stringer.
+		return parseBasicTypeField(t, f, typeName)
+	}
+
 	parseRecursively = func(s *types.Struct, typeName string) ([]field, error) {
 		var fields []field
 
 		for i := range s.NumFields() {
 			f := s.Field(i)
 
+			tag, err := parseFieldTag(s.Tag(i))
+			if err != nil {
+				return nil, parsingError{
+					typeName:  typeName,
+					fieldName: f.Name(),
+					msg:       err.Error(),
+				}
+			}
+
+			if tag.skip {
+				continue
+			}
+
 			var parsedField field
-			var err error
 
-			switch t := f.Type().(type) {
-			case *types.Named: // when the field is a Struct
-				parsedField, err = parseStructField(t, f, typeName)
+			switch t := types.Unalias(f.Type()).(type) {
+			case *types.Named:
+				parsedField, err = parseNamedField(t, f, typeName)
 			case *types.Basic: // when the field is a basic type like int, string, etc.
 				parsedField, err = parseBasicTypeField(t, f, typeName)
 			case *types.Slice: // when the field is a slice of basic type like []int.
 				parsedField, err = parseSliceField(t, f, typeName)
+			case *types.Map: // when the field is a map of string to basic type like map[string]int64.
+				parsedField, err = parseMapField(t, f, typeName)
+			case *types.Pointer: // when the field is a pointer to basic type like *int64.
+				parsedField, err = parsePointerField(t, f, typeName)
 			default:
 				err = parsingError{
 					typeName:  typeName,
 					fieldName: f.Name(),
-					msg:       "must be of embedded struct, basic type or slice of basic type, got " + f.Type().String(),
+					msg: "must be of embedded struct, basic type, pointer to basic type, slice of basic type " +
+						"or map of string to basic type, got " + f.Type().String(),
 				}
 			}
 
@@ -409,17 +825,61 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 				return nil, err
 			}
 
+			if parsedField.embeddedStruct {
+				if tag != (fieldTag{}) {
+					return nil, parsingError{
+						typeName:  typeName,
+						fieldName: f.Name(),
+						msg:       "embedded structs only support the \"-\" telemetry tag",
+					}
+				}
+
+				fields = append(fields, parsedField)
+				continue
+			}
+
+			parsedField.key = tag.key
+			parsedField.omitEmpty = tag.omitEmpty
+
+			parsedField.validation, err = parseFieldValidation(parsedField, tag, typeName, docStringProvider)
+			if err != nil {
+				return nil, err
+			}
+
+			if parsedField.timeKind != timeKindNone {
+				parsedField.timePrecision = precision
+			}
+
+			parsedField.docString = tag.doc
+			if parsedField.docString == "" {
+				parsedField.docString, err = docStringProvider.getDocString(typeName, f.Name())
+				if err != nil {
+					return nil, parsingError{
+						typeName:  typeName,
+						fieldName: f.Name(),
+						msg:       err.Error(),
+					}
+				}
+			}
+
 			fields = append(fields, parsedField)
 
-			if owner, exists := nameOwners[f.Name()]; exists {
+			key := parsedField.attributeKey()
+
+			if owner, exists := keyOwners[key]; exists {
+				msg := "already exists in " + owner
+				if key != f.Name() {
+					msg = fmt.Sprintf("key %s already exists in %s", key, owner)
+				}
+
 				return nil, parsingError{
 					typeName:  typeName,
 					fieldName: f.Name(),
-					msg:       "already exists in " + owner,
+					msg:       msg,
 				}
 			}
 
-			nameOwners[f.Name()] = typeName
+			keyOwners[key] = typeName
 		}
 
 		return fields, nil
@@ -430,23 +890,185 @@ func parseStruct(s *types.Struct, typeName string, docStringProvider *docStringF
 		return nil, fmt.Errorf("failed to parse struct: %w", err)
 	}
 
+	// the keys are checked again after the key style is applied, but the keys set by the telemetry tags are
+	// reported here
+	if err := checkMapKeyPrefixes(fields); err != nil {
+		return nil, fmt.Errorf("failed to parse struct: type %s: %w", typeName, err)
+	}
+
 	return fields, nil
 }
 
+// parseFieldValidation parses the validation rules of the field from its telemetry tag and its marker comments,
+// and checks that they can be applied to its type.
+func parseFieldValidation(
+	f field,
+	tag fieldTag,
+	typeName string,
+	docStringProvider *docStringFieldsProvider,
+) (fieldValidation, error) {
+	markers, err := docStringProvider.getFieldMarkers(typeName, f.name)
+	if err != nil {
+		return fieldValidation{}, parsingError{typeName: typeName, fieldName: f.name, msg: err.Error()}
+	}
+
+	validation := tag.validation
+
+	if err := validation.parseFieldMarkers(markers); err != nil {
+		return fieldValidation{}, parsingError{typeName: typeName, fieldName: f.name, msg: err.Error()}
+	}
+
+	f.validation = validation
+
+	if err := checkFieldValidation(f); err != nil {
+		return fieldValidation{}, parsingError{typeName: typeName, fieldName: f.name, msg: err.Error()}
+	}
+
+	return validation, nil
+}
+
+// stringerType is the fmt.Stringer interface.
+var stringerType = func() *types.Interface {
+	results := types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String]))
+	stringMethod := types.NewFunc(token.NoPos, nil, "String", types.NewSignatureType(nil, nil, nil, nil, results, false))
+
+	return types.NewInterfaceType([]*types.Func{stringMethod}, nil).Complete()
+}()
+
+// valueType is the parsed type of a field value.
+type valueType struct {
+	named    *namedType
+	kind     types.BasicKind
+	timeKind timeKind
+}
+
+// parseValueType parses the type of a value of a field: the field itself, an element of a slice or a value of a map.
+// The type must be of an allowed basic kind, a named type with such an underlying type, time.Time, time.Duration
+// or a type that implements fmt.Stringer. time.Time and time.Duration are converted to int64, fmt.Stringer types are
+// converted to string.
+// subject is what has the type (e.g. "field" or "map value"), fieldType is the type of the field.
+func parseValueType(t types.Type, subject string, fieldType types.Type) (valueType, error) {
+	t = types.Unalias(t)
+
+	named, isNamed := t.(*types.Named)
+
+	if isNamed && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "time" {
+		switch named.Obj().Name() {
+		case "Time":
+			return valueType{kind: types.Int64, timeKind: timeKindTimestamp}, nil
+		case "Duration":
+			return valueType{kind: types.Int64, timeKind: timeKindDuration}, nil
+		}
+	}
+
+	if isNamed && types.Implements(named, stringerType) {
+		return valueType{kind: types.String, named: newNamedType(named, true)}, nil
+	}
+
+	basic, ok := t.Underlying().(*types.Basic)
+	if !ok {
+		return valueType{}, fmt.Errorf(
+			"type of %s must be one of %s, got %s",
+			subject,
+			supportedKinds,
+			fieldType.String(),
+		)
+	}
+
+	if _, allowed := allowedBasicKinds[basic.Kind()]; !allowed {
+		return valueType{}, errors.New(getUnsupportedKindMsg(subject, basic.Kind(), fieldType))
+	}
+
+	if !isNamed {
+		return valueType{kind: basic.Kind()}, nil
+	}
+
+	return valueType{kind: basic.Kind(), named: newNamedType(named, false)}, nil
+}
+
+// newNamedType creates a new namedType for the named type t.
+func newNamedType(t *types.Named, stringer bool) *namedType {
+	nt := &namedType{
+		packagePath: t.Obj().Pkg().Path(),
+		name:        t.Obj().Name(),
+		stringer:    stringer,
+	}
+
+	if stringer {
+		return nt
+	}
+
+	// find the typed constants of the type in its package
+	scope := t.Obj().Pkg().Scope()
+
+	for _, name := range scope.Names() {
+		c, ok := scope.Lookup(name).(*types.Const)
+		if !ok || !types.Identical(c.Type(), t) || c.Val().Kind() != constant.String {
+			continue
+		}
+
+		value := constant.StringVal(c.Val())
+		if !slices.Contains(nt.enumValues, value) {
+			nt.enumValues = append(nt.enumValues, value)
+		}
+	}
+
+	sort.Strings(nt.enumValues)
+
+	return nt
+}
+
 // allowedBasicKinds is a map of allowed basic types.
 // Includes all supported types from go.opentelemetry.io/otel/attribute
-// except for int.
-// Since int size is platform dependent and because the size is required for Avro scheme, we don't use int.
+// and the integer and float types that can be safely widened to int64 and float64.
+// Since int size is platform dependent, int is widened to int64 as well.
 var allowedBasicKinds = map[types.BasicKind]struct{}{
+	types.Int:     {},
+	types.Int8:    {},
+	types.Int16:   {},
+	types.Int32:   {},
 	types.Int64:   {},
+	types.Uint8:   {},
+	types.Uint16:  {},
+	types.Uint32:  {},
+	types.Float32: {},
 	types.Float64: {},
 	types.String:  {},
 	types.Bool:    {},
 }
 
+// overflowingBasicKinds is a map of unsigned integer types that can't be widened to int64 without overflow.
+var overflowingBasicKinds = map[types.BasicKind]struct{}{
+	types.Uint:    {},
+	types.Uint64:  {},
+	types.Uintptr: {},
+}
+
+// getUnsupportedKindMsg returns the error message for a kind that is not in allowedBasicKinds.
+// subject is what has the kind (e.g. "field" or "map value").
+func getUnsupportedKindMsg(subject string, kind types.BasicKind, t types.Type) string {
+	if _, overflows := overflowingBasicKinds[kind]; overflows {
+		return fmt.Sprintf(
+			"type of %s can overflow int64, got %s; use int64 or a smaller unsigned type",
+			subject,
+			t.String(),
+		)
+	}
+
+	return fmt.Sprintf("type of %s must be one of %s, got %s", subject, supportedKinds, t.String())
+}
+
 var supportedKinds = func() string {
 	kindsToString := map[types.BasicKind]string{
+		types.Int:     "int",
+		types.Int8:    "int8",
+		types.Int16:   "int16",
+		types.Int32:   "int32",
 		types.Int64:   "int64",
+		types.Uint8:   "uint8",
+		types.Uint16:  "uint16",
+		types.Uint32:  "uint32",
+		types.Float32: "float32",
 		types.Float64: "float64",
 		types.String:  "string",
 		types.Bool:    "bool",
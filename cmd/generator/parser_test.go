@@ -49,7 +49,19 @@ type EmbeddedBasicType struct {
 }
 
 type UnsupportedBasicType struct {
-	Counter int
+	Counter complex128
+}
+
+type OverflowingBasicType struct {
+	Counter uint64
+}
+
+type OverflowingSliceType struct {
+	Counters []uint
+}
+
+type OverflowingMapType struct {
+	Counters map[string]uintptr
 }
 
 type MissingBasicFieldDocString struct {
@@ -71,7 +83,121 @@ type UnsupportedSliceType struct {
 }
 
 type UnsupportedBasicTypeSlice struct {
-	Counters []int
+	Counters []complex64
+}
+
+type UnsupportedMapKeyType struct {
+	// Counters is a map of counters.
+	Counters map[int64]int64
+}
+
+type UnsupportedMapValueType struct {
+	// Counters is a map of counters.
+	Counters map[string]complex64
+}
+
+type UnexportedMapField struct {
+	counters map[string]int64 //nolint:unused
+}
+
+type MissingMapFieldDocString struct {
+	Counters map[string]int64 // doc string above is missing
+}
+
+type Channel chan int
+
+type UnsupportedNamedType struct {
+	// Channel is a channel.
+	Channel Channel
+}
+
+type UnsupportedNamedSliceType struct {
+	// Channels is a slice of channels.
+	Channels []Channel
+}
+
+type UnsupportedPointerType struct {
+	// Strings is a pointer to a slice of strings.
+	Strings *[]string
+}
+
+type EmbeddedPointer struct {
+	*SomeStruct
+}
+
+type MissingPointerFieldDocString struct {
+	Counter *int64 // doc string above is missing
+}
+
+type InvalidTagKey struct {
+	// Counter is a counter.
+	Counter int64 `telemetry:"my-counter"`
+}
+
+type UnknownTagOption struct {
+	// Counter is a counter.
+	Counter int64 `telemetry:",omitnil"`
+}
+
+type UnknownFieldMarker struct {
+	// Counter is a counter.
+	// +telemetry:minimum=0
+	Counter int64
+}
+
+type InvalidValidationRule struct {
+	// Counter is a counter.
+	Counter int64 `telemetry:",min=zero"`
+}
+
+type MisappliedValidationRule struct {
+	// Name is a name.
+	// +telemetry:min=0
+	Name string
+}
+
+type MisappliedMaxItems struct {
+	// Counter is a counter.
+	Counter int64 `telemetry:",maxItems=1"`
+}
+
+type FractionalIntegerBound struct {
+	// Counter is a counter.
+	// +telemetry:max=0.5
+	Counter int64
+}
+
+type OutOfRangeBound struct {
+	// Counter is a counter.
+	// +telemetry:min=1000
+	Counter int8
+}
+
+type NegativeUnsignedBound struct {
+	// Counter is a counter.
+	Counter uint32 `telemetry:",min=-1"`
+}
+
+type EmptyTagDoc struct {
+	Counter int64 `telemetry:",doc= "`
+}
+
+type TaggedEmbeddedStruct struct {
+	EmbeddedDuplicateFields `telemetry:",omitempty"`
+}
+
+type DuplicateTagKeys struct {
+	// Counter is a counter.
+	Counter int64
+	// OtherCounter is another counter.
+	OtherCounter int64 `telemetry:"Counter"`
+}
+
+type MapKeyPrefix struct {
+	// Counts are the counts by name.
+	Counts map[string]int64
+	// OtherCount is another count.
+	OtherCount int64 `telemetry:"Counts.other"`
 }
 
 type DuplicateFields struct {
@@ -85,6 +211,31 @@ type EmbeddedDuplicateFields struct {
 	Counter int64
 }
 
+// DiscoveredData is discovered by the parser.
+//
+// +telemetry:generate
+type DiscoveredData struct {
+	// Counter is a counter.
+	Counter int64
+}
+
+type (
+	// AnotherDiscoveredData is discovered by the parser.
+	//
+	// +telemetry:generate
+	AnotherDiscoveredData struct {
+		// Counter is a counter.
+		Counter int64
+	}
+
+	// NotDiscoveredData is not discovered by the parser, because the marker is not on its own line.
+	// It is not +telemetry:generate
+	NotDiscoveredData struct {
+		// Counter is a counter.
+		Counter int64
+	}
+)
+
 func TestParseErrors(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -133,9 +284,25 @@ func TestParseErrors(t *testing.T) {
 			typeName:       "EmbeddedBasicType",
 		},
 		{
-			name:           "unsupported basic type",
-			expectedErrMsg: "field Counter: type of field must be one of bool, float64, int64, string, got int",
-			typeName:       "UnsupportedBasicType",
+			name: "unsupported basic type",
+			expectedErrMsg: "field Counter: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, got complex128",
+			typeName: "UnsupportedBasicType",
+		},
+		{
+			name:           "overflowing basic type",
+			expectedErrMsg: "field Counter: type of field can overflow int64, got uint64",
+			typeName:       "OverflowingBasicType",
+		},
+		{
+			name:           "overflowing slice type",
+			expectedErrMsg: "field Counters: type of field can overflow int64, got []uint",
+			typeName:       "OverflowingSliceType",
+		},
+		{
+			name:           "overflowing map type",
+			expectedErrMsg: "field Counters: type of map value can overflow int64, got map[string]uintptr",
+			typeName:       "OverflowingMapType",
 		},
 		{
 			name:           "missing field doc string",
@@ -154,14 +321,132 @@ func TestParseErrors(t *testing.T) {
 		},
 		{
 			name: "unsupported slice type",
-			expectedErrMsg: "field Structs: type of field must be one of bool, float64, int64, string, " +
+			expectedErrMsg: "field Structs: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, " +
 				"got []github.com/nginx/telemetry-exporter/cmd/generator.SomeStruct",
 			typeName: "UnsupportedSliceType",
 		},
 		{
-			name:           "unsupported basic type slice",
-			expectedErrMsg: "field Counters: type of field must be one of bool, float64, int64, string, got []int",
-			typeName:       "UnsupportedBasicTypeSlice",
+			name: "unsupported basic type slice",
+			expectedErrMsg: "field Counters: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, got []complex64",
+			typeName: "UnsupportedBasicTypeSlice",
+		},
+		{
+			name:           "unsupported map key type",
+			expectedErrMsg: "field Counters: map key must be string, got int64",
+			typeName:       "UnsupportedMapKeyType",
+		},
+		{
+			name: "unsupported map value type",
+			expectedErrMsg: "field Counters: type of map value must be one of bool, float32, float64, int, int16, " +
+				"int32, int64, int8, string, uint16, uint32, uint8, got map[string]complex64",
+			typeName: "UnsupportedMapValueType",
+		},
+		{
+			name:           "unexported map field",
+			expectedErrMsg: "field counters: must be exported",
+			typeName:       "UnexportedMapField",
+		},
+		{
+			name:           "missing map field doc string",
+			expectedErrMsg: "field Counters: doc string not found",
+			typeName:       "MissingMapFieldDocString",
+		},
+		{
+			name: "unsupported named type",
+			expectedErrMsg: "field Channel: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, got github.com/nginx/telemetry-exporter/cmd/generator.Channel",
+			typeName: "UnsupportedNamedType",
+		},
+		{
+			name: "unsupported named slice type",
+			expectedErrMsg: "field Channels: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, got []github.com/nginx/telemetry-exporter/cmd/generator.Channel",
+			typeName: "UnsupportedNamedSliceType",
+		},
+		{
+			name: "unsupported pointer type",
+			expectedErrMsg: "field Strings: type of field must be one of bool, float32, float64, int, int16, int32, " +
+				"int64, int8, string, uint16, uint32, uint8, got *[]string",
+			typeName: "UnsupportedPointerType",
+		},
+		{
+			name:           "embedded pointer",
+			expectedErrMsg: "field SomeStruct: embedded pointers are not allowed",
+			typeName:       "EmbeddedPointer",
+		},
+		{
+			name:           "missing pointer field doc string",
+			expectedErrMsg: "field Counter: doc string not found",
+			typeName:       "MissingPointerFieldDocString",
+		},
+		{
+			name:           "invalid tag key",
+			expectedErrMsg: `field Counter: telemetry tag key "my-counter" must match`,
+			typeName:       "InvalidTagKey",
+		},
+		{
+			name:           "unknown tag option",
+			expectedErrMsg: `field Counter: unknown telemetry tag option "omitnil"`,
+			typeName:       "UnknownTagOption",
+		},
+		{
+			name:           "unknown field marker",
+			expectedErrMsg: "field Counter: unknown marker +telemetry:minimum",
+			typeName:       "UnknownFieldMarker",
+		},
+		{
+			name: "invalid validation rule",
+			expectedErrMsg: `field Counter: telemetry tag option "min=zero": ` +
+				"validation rule min must be a finite number, got zero",
+			typeName: "InvalidValidationRule",
+		},
+		{
+			name:           "misapplied validation rule",
+			expectedErrMsg: "field Name: validation rule min is only supported for numbers and pointers to numbers",
+			typeName:       "MisappliedValidationRule",
+		},
+		{
+			name:           "misapplied maxItems",
+			expectedErrMsg: "field Counter: validation rule maxItems is only supported for slices and maps",
+			typeName:       "MisappliedMaxItems",
+		},
+		{
+			name:           "fractional integer bound",
+			expectedErrMsg: "field Counter: validation rule max must be an integer for integer fields, got 0.5",
+			typeName:       "FractionalIntegerBound",
+		},
+		{
+			name:           "out of range bound",
+			expectedErrMsg: "field Counter: validation rule min is out of the range of int8 fields, got 1000",
+			typeName:       "OutOfRangeBound",
+		},
+		{
+			name:           "negative unsigned bound",
+			expectedErrMsg: "field Counter: validation rule min must not be negative for unsigned integer fields, got -1",
+			typeName:       "NegativeUnsignedBound",
+		},
+		{
+			name:           "empty tag doc",
+			expectedErrMsg: "field Counter: telemetry tag doc is empty",
+			typeName:       "EmptyTagDoc",
+		},
+		{
+			name:           "tagged embedded struct",
+			expectedErrMsg: `field EmbeddedDuplicateFields: embedded structs only support the "-" telemetry tag`,
+			typeName:       "TaggedEmbeddedStruct",
+		},
+		{
+			name: "duplicate tag keys",
+			expectedErrMsg: "field OtherCounter: key Counter already exists in " +
+				"github.com/nginx/telemetry-exporter/cmd/generator.DuplicateTagKeys",
+			typeName: "DuplicateTagKeys",
+		},
+		{
+			name:           "key in the attributes of map field",
+			expectedErrMsg: "MapKeyPrefix: field OtherCount: key Counts.other is in the attributes of map field Counts",
+			typeName:       "MapKeyPrefix",
 		},
 		{
 			name: "duplicate fields",
@@ -183,7 +468,7 @@ func TestParseErrors(t *testing.T) {
 
 			cfg := parsingConfig{
 				pkgName:    "main",
-				typeName:   test.typeName,
+				typeNames:  []string{test.typeName},
 				loadTests:  true,
 				buildFlags: []string{"-tags=generator"},
 			}
@@ -201,7 +486,7 @@ func TestParseLoadingFailures(t *testing.T) {
 
 	cfg := parsingConfig{
 		pkgName:    "notfound",
-		typeName:   "Data",
+		typeNames:  []string{"Data"},
 		loadTests:  true,
 		buildFlags: []string{"-tags=generator"},
 	}
@@ -211,13 +496,99 @@ func TestParseLoadingFailures(t *testing.T) {
 	g.Expect(err).To(MatchError(ContainSubstring("package notfound not found")))
 }
 
+func TestParseMultipleTypes(t *testing.T) {
+	t.Parallel()
+
+	counterFields := []field{
+		{
+			docString: "Counter is a counter.",
+			name:      "Counter",
+			fieldType: types.Int64,
+		},
+	}
+
+	generate := typeMarkers{generate: true}
+
+	tests := []struct {
+		name          string
+		typeNames     []string
+		expectedTypes []parsedType
+		discover      bool
+	}{
+		{
+			name:      "type names",
+			typeNames: []string{"EmbeddedDuplicateFields", "DiscoveredData"},
+			expectedTypes: []parsedType{
+				{name: "EmbeddedDuplicateFields", fields: counterFields},
+				{name: "DiscoveredData", fields: counterFields, markers: generate},
+			},
+		},
+		{
+			name:     "discovered types",
+			discover: true,
+			expectedTypes: []parsedType{
+				{name: "DiscoveredData", fields: counterFields, markers: generate},
+				{name: "AnotherDiscoveredData", fields: counterFields, markers: generate},
+			},
+		},
+		{
+			name:      "type names followed by discovered types",
+			typeNames: []string{"EmbeddedDuplicateFields", "AnotherDiscoveredData"},
+			discover:  true,
+			expectedTypes: []parsedType{
+				{name: "EmbeddedDuplicateFields", fields: counterFields},
+				{name: "AnotherDiscoveredData", fields: counterFields, markers: generate},
+				{name: "DiscoveredData", fields: counterFields, markers: generate},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			cfg := parsingConfig{
+				pkgName:    "main",
+				typeNames:  test.typeNames,
+				loadTests:  true,
+				buildFlags: []string{"-tags=generator"},
+				discover:   test.discover,
+			}
+
+			result, err := parse(cfg)
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result.types).To(Equal(test.expectedTypes))
+		})
+	}
+}
+
+func TestParseNoTypes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := parsingConfig{
+		pkgName:     "subtests",
+		loadPattern: "github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests",
+		buildFlags:  []string{"-tags=generator"},
+		discover:    true,
+	}
+
+	_, err := parse(cfg)
+
+	g.Expect(err).To(MatchError("no types to parse"))
+}
+
+const testsPackagePath = "github.com/nginx/telemetry-exporter/cmd/generator/tests"
+
 func TestParseSuccess(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
 
 	cfg := parsingConfig{
 		pkgName:     "tests",
-		typeName:    "Data",
+		typeNames:   []string{"Data"},
 		loadPattern: "github.com/nginx/telemetry-exporter/cmd/generator/tests",
 		buildFlags:  []string{"-tags=generator"},
 	}
@@ -237,6 +608,7 @@ func TestParseSuccess(t *testing.T) {
 			docString:            "AnotherSomeInt is an int64 field.",
 			name:                 "AnotherSomeInt",
 			fieldType:            types.Int64,
+			validation:           fieldValidation{min: "0"},
 			slice:                false,
 			embeddedStruct:       false,
 			embeddedStructFields: nil,
@@ -296,6 +668,7 @@ func TestParseSuccess(t *testing.T) {
 			docString:            "SomeString is a string field.",
 			name:                 "SomeString",
 			fieldType:            types.String,
+			validation:           fieldValidation{required: true},
 			slice:                false,
 			embeddedStruct:       false,
 			embeddedStructFields: nil,
@@ -328,6 +701,7 @@ func TestParseSuccess(t *testing.T) {
 			docString:            "SomeStrings is a slice of strings.",
 			name:                 "SomeStrings",
 			fieldType:            types.String,
+			validation:           fieldValidation{maxItems: "5"},
 			slice:                true,
 			embeddedStruct:       false,
 			embeddedStructFields: nil,
@@ -357,18 +731,290 @@ func TestParseSuccess(t *testing.T) {
 			embeddedStructFields: nil,
 		},
 		{
-			docString:            "",
-			name:                 "AnotherData",
-			fieldType:            0,
+			docString:            "SomeStringMap is a map of strings.",
+			name:                 "SomeStringMap",
+			fieldType:            types.String,
+			stringMap:            true,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeIntMap is a map of int64.",
+			name:                 "SomeIntMap",
+			fieldType:            types.Int64,
+			validation:           fieldValidation{maxItems: "10"},
+			stringMap:            true,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeNativeInt is an int field.",
+			name:                 "SomeNativeInt",
+			fieldType:            types.Int,
 			slice:                false,
-			embeddedStruct:       true,
-			embeddedStructFields: expectedEmbeddedStructFields,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeInt8 is an int8 field.",
+			name:                 "SomeInt8",
+			fieldType:            types.Int8,
+			validation:           fieldValidation{min: "-10", max: "10"},
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeInt16 is an int16 field.",
+			name:                 "SomeInt16",
+			fieldType:            types.Int16,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeInt32 is an int32 field.",
+			name:                 "SomeInt32",
+			fieldType:            types.Int32,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeUint8 is a uint8 field.",
+			name:                 "SomeUint8",
+			fieldType:            types.Uint8,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeUint16 is a uint16 field.",
+			name:                 "SomeUint16",
+			fieldType:            types.Uint16,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeUint32 is a uint32 field.",
+			name:                 "SomeUint32",
+			fieldType:            types.Uint32,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeFloat32 is a float32 field.",
+			name:                 "SomeFloat32",
+			fieldType:            types.Float32,
+			slice:                false,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeInt32s is a slice of int32.",
+			name:                 "SomeInt32s",
+			fieldType:            types.Int32,
+			slice:                true,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeFloat32s is a slice of float32.",
+			name:                 "SomeFloat32s",
+			fieldType:            types.Float32,
+			slice:                true,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString:            "SomeUint32Map is a map of uint32.",
+			name:                 "SomeUint32Map",
+			fieldType:            types.Uint32,
+			stringMap:            true,
+			embeddedStruct:       false,
+			embeddedStructFields: nil,
+		},
+		{
+			docString: "SomePlatform is a named string field.",
+			name:      "SomePlatform",
+			fieldType: types.String,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Platform",
+				enumValues:  []string{"aws", "gcp", "other"},
+			},
+		},
+		{
+			docString: "SomeLevel is a fmt.Stringer field.",
+			name:      "SomeLevel",
+			fieldType: types.String,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Level",
+				stringer:    true,
+			},
+		},
+		{
+			docString: "SomeCount is a named int32 field.",
+			name:      "SomeCount",
+			fieldType: types.Int32,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Count",
+			},
+		},
+		{
+			docString: "SomePlatforms is a slice of a named string type.",
+			name:      "SomePlatforms",
+			fieldType: types.String,
+			slice:     true,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Platform",
+				enumValues:  []string{"aws", "gcp", "other"},
+			},
+		},
+		{
+			docString: "SomeLevelMap is a map of a fmt.Stringer type.",
+			name:      "SomeLevelMap",
+			fieldType: types.String,
+			stringMap: true,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Level",
+				stringer:    true,
+			},
+		},
+		{
+			docString: "SomeStringPointer is a pointer to a string.",
+			name:      "SomeStringPointer",
+			fieldType: types.String,
+			pointer:   true,
+		},
+		{
+			docString: "SomeIntPointer is a pointer to an int64.",
+			name:      "SomeIntPointer",
+			fieldType: types.Int64,
+			pointer:   true,
+		},
+		{
+			docString: "SomeFloatPointer is a pointer to a float64.",
+			name:      "SomeFloatPointer",
+			fieldType: types.Float64,
+			pointer:   true,
+		},
+		{
+			docString: "SomeBoolPointer is a pointer to a bool.",
+			name:      "SomeBoolPointer",
+			fieldType: types.Bool,
+			pointer:   true,
+		},
+		{
+			docString:  "SomeCountPointer is a pointer to a named int32 type.",
+			name:       "SomeCountPointer",
+			fieldType:  types.Int32,
+			validation: fieldValidation{min: "1"},
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Count",
+			},
+			pointer: true,
+		},
+		{
+			docString: "SomeLevelPointer is a pointer to a fmt.Stringer type.",
+			name:      "SomeLevelPointer",
+			fieldType: types.String,
+			named: &namedType{
+				packagePath: testsPackagePath,
+				name:        "Level",
+				stringer:    true,
+			},
+			pointer: true,
+		},
+		{
+			docString:     "SomeTime is a time.Time field.",
+			name:          "SomeTime",
+			fieldType:     types.Int64,
+			timeKind:      timeKindTimestamp,
+			timePrecision: timePrecisionMilliseconds,
+		},
+		{
+			docString:     "SomeDuration is a time.Duration field.",
+			name:          "SomeDuration",
+			fieldType:     types.Int64,
+			timeKind:      timeKindDuration,
+			timePrecision: timePrecisionMilliseconds,
+		},
+		{
+			docString:     "SomeTimePointer is a pointer to a time.Time.",
+			name:          "SomeTimePointer",
+			fieldType:     types.Int64,
+			timeKind:      timeKindTimestamp,
+			timePrecision: timePrecisionMilliseconds,
+			pointer:       true,
+		},
+		{
+			docString:     "SomeDurations is a slice of time.Duration.",
+			name:          "SomeDurations",
+			fieldType:     types.Int64,
+			timeKind:      timeKindDuration,
+			timePrecision: timePrecisionMilliseconds,
+			slice:         true,
+		},
+		{
+			docString: "SomeKeyedString is a string field with a custom attribute key.",
+			name:      "SomeKeyedString",
+			key:       "some_keyed_string",
+			fieldType: types.String,
+		},
+		{
+			docString: "SomeOmittedInt is an int64 field that is omitted when zero.",
+			name:      "SomeOmittedInt",
+			fieldType: types.Int64,
+			omitEmpty: true,
+		},
+		{
+			docString: "SomeOmittedStrings is a slice of strings that is omitted when empty.",
+			name:      "SomeOmittedStrings",
+			key:       "some_omitted_strings",
+			fieldType: types.String,
+			slice:     true,
+			omitEmpty: true,
+		},
+		{
+			docString: "SomeDocumentedBool is a bool field, documented in the tag.",
+			name:      "SomeDocumentedBool",
+			fieldType: types.Bool,
+		},
+		{
+			docString:             "",
+			name:                  "AnotherData",
+			fieldType:             0,
+			slice:                 false,
+			embeddedStruct:        true,
+			embeddedStructFields:  expectedEmbeddedStructFields,
+			embeddedStructPackage: "github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests",
+			embeddedStructDocString: "AnotherData is a struct that can be exported by a struct in another package " +
+				"to test cross-package referencing\nwhen generating code and scheme.\n" +
+				"AnotherData includes a field of each supported data type except an embedded struct.",
 		},
 	}
 
 	expectedResult := parsingResult{
 		packagePath: "github.com/nginx/telemetry-exporter/cmd/generator/tests",
-		fields:      expectedFields,
+		types: []parsedType{
+			{
+				name:   "Data",
+				fields: expectedFields,
+				markers: typeMarkers{
+					schemeProtocol: "NGFProductTelemetry",
+					schemeDataType: "ngf-product-telemetry",
+					buildTags:      "generator",
+				},
+			},
+		},
 	}
 
 	result, err := parse(cfg)
@@ -376,3 +1022,62 @@ func TestParseSuccess(t *testing.T) {
 	g.Expect(err).ToNot(HaveOccurred())
 	g.Expect(expectedResult).To(Equal(result))
 }
+
+func TestParseFieldTag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		structTag string
+		expected  fieldTag
+	}{
+		{
+			name:      "no tag",
+			structTag: `json:"counter"`,
+			expected:  fieldTag{},
+		},
+		{
+			name:      "skip",
+			structTag: `telemetry:"-"`,
+			expected:  fieldTag{skip: true},
+		},
+		{
+			name:      "key",
+			structTag: `telemetry:"counter"`,
+			expected:  fieldTag{key: "counter"},
+		},
+		{
+			name:      "omitempty",
+			structTag: `telemetry:",omitempty"`,
+			expected:  fieldTag{omitEmpty: true},
+		},
+		{
+			name:      "validation rules",
+			structTag: `telemetry:",required,min=-1.5,max=10,maxItems=100"`,
+			expected: fieldTag{
+				validation: fieldValidation{required: true, min: "-1.5", max: "10", maxItems: "100"},
+			},
+		},
+		{
+			name:      "all options",
+			structTag: `telemetry:"counter,omitempty,doc=Counter counts, and counts."`,
+			expected: fieldTag{
+				key:       "counter",
+				omitEmpty: true,
+				doc:       "Counter counts, and counts.",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			tag, err := parseFieldTag(test.structTag)
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tag).To(Equal(test.expected))
+		})
+	}
+}
@@ -0,0 +1,339 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/types"
+	"io"
+	"io/fs"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	// protoFirstReservedNumber and protoLastReservedNumber are the range of field numbers reserved by Protobuf.
+	protoFirstReservedNumber = 19000
+	protoLastReservedNumber  = 19999
+)
+
+// protoLock is the lock file of a Protobuf schema, which keeps the field numbers stable across regenerations.
+// The numbers of removed fields are reserved, so that they're never reused.
+type protoLock struct {
+	// Messages are the locks of the messages, by their full name, like Data.AnotherData.
+	Messages map[string]protoLockMessage `json:"messages"`
+}
+
+// protoLockMessage is the lock of a message.
+type protoLockMessage struct {
+	// Fields are the numbers of the fields, by their name.
+	Fields map[string]int `json:"fields"`
+	// Reserved are the numbers of the removed fields.
+	Reserved []int `json:"reserved,omitempty"`
+}
+
+// assignNumbers returns the lock of the message with the fields, where the fields in the lock keep their numbers,
+// the numbers of the removed fields are reserved and the new fields get the next unused numbers.
+func (m protoLockMessage) assignNumbers(fieldNames []string) protoLockMessage {
+	result := protoLockMessage{
+		Fields:   make(map[string]int, len(fieldNames)),
+		Reserved: slices.Clone(m.Reserved),
+	}
+
+	lastNumber := 0
+	for _, number := range m.Reserved {
+		lastNumber = max(lastNumber, number)
+	}
+
+	for name, number := range m.Fields {
+		lastNumber = max(lastNumber, number)
+
+		if !slices.Contains(fieldNames, name) {
+			result.Reserved = append(result.Reserved, number)
+		}
+	}
+
+	for _, name := range fieldNames {
+		if number, exists := m.Fields[name]; exists {
+			result.Fields[name] = number
+			continue
+		}
+
+		lastNumber++
+		if lastNumber >= protoFirstReservedNumber && lastNumber <= protoLastReservedNumber {
+			lastNumber = protoLastReservedNumber + 1
+		}
+
+		result.Fields[name] = lastNumber
+	}
+
+	slices.Sort(result.Reserved)
+
+	return result
+}
+
+// loadProtoLock loads the lock file, returning an empty lock if the file doesn't exist.
+func loadProtoLock(path string) (protoLock, error) {
+	content, err := os.ReadFile(path) //nolint:gosec // the path of the lock file is set by the user
+	if errors.Is(err, fs.ErrNotExist) {
+		return protoLock{}, nil
+	}
+	if err != nil {
+		return protoLock{}, fmt.Errorf("failed to read lock file: %w", err)
+	}
+
+	var lock protoLock
+
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return protoLock{}, fmt.Errorf("failed to decode lock file %s: %w", path, err)
+	}
+
+	return lock, nil
+}
+
+// encodeProtoLock encodes the lock file.
+func encodeProtoLock(writer io.Writer, lock protoLock) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(lock); err != nil {
+		return fmt.Errorf("failed to encode lock file: %w", err)
+	}
+
+	return nil
+}
+
+func getProtoScalarType(kind types.BasicKind) string {
+	switch kind {
+	case types.Int, types.Int64:
+		return "int64"
+	case types.Int8, types.Int16, types.Int32:
+		return "int32"
+	case types.Uint8, types.Uint16, types.Uint32:
+		return "uint32"
+	case types.Float32:
+		return "float"
+	case types.Float64:
+		return "double"
+	case types.String:
+		return "string"
+	case types.Bool:
+		return "bool"
+	default:
+		panic(fmt.Sprintf("unexpected kind %v", kind))
+	}
+}
+
+// protoField is a field of a Protobuf message. Exactly one of the scalar type and the message is set.
+type protoField struct {
+	message *protoMessage
+	comment string
+	// label is the label of the field: optional, repeated or empty for maps and messages.
+	label      string
+	scalarType string
+	name       string
+}
+
+// protoMessage is a Protobuf message.
+type protoMessage struct {
+	comment  string
+	name     string
+	fullName string
+	fields   []protoField
+}
+
+type protoGenConfig struct {
+	lock      protoLock
+	namespace string
+	record    string
+	fields    []field
+}
+
+// createProtoMessage creates the message of the fields, where the fields of an embedded struct are in a nested
+// message named after the struct. All fields are optional, like in the Avro scheme.
+func createProtoMessage(name, fullName, comment string, fields []field) (*protoMessage, error) {
+	msg := &protoMessage{
+		comment:  comment,
+		name:     name,
+		fullName: fullName,
+	}
+
+	// fields and nested messages share the scope of the message
+	owners := make(map[string]string, len(fields))
+
+	addName := func(name, owner string) error {
+		if existing, exists := owners[name]; exists {
+			return fmt.Errorf("message %s: name %s of %s is already used by %s", fullName, name, owner, existing)
+		}
+		owners[name] = owner
+
+		return nil
+	}
+
+	for _, f := range fields {
+		if f.embeddedStruct {
+			nested, err := createProtoMessage(
+				f.name,
+				fullName+"."+f.name,
+				fmt.Sprintf("%s holds the fields of the embedded struct %s.", f.name, f.name),
+				f.embeddedStructFields,
+			)
+			if err != nil {
+				return nil, err
+			}
+
+			fieldName := formatKey(f.name, keyStyleSnake)
+
+			if err := addName(f.name, "message "+f.name); err != nil {
+				return nil, err
+			}
+			if err := addName(fieldName, "field "+f.name); err != nil {
+				return nil, err
+			}
+
+			msg.fields = append(msg.fields, protoField{
+				comment: nested.comment,
+				name:    fieldName,
+				message: nested,
+			})
+
+			continue
+		}
+
+		pf := protoField{
+			comment:    f.docString,
+			name:       getAvroName(f.attributeKey()),
+			scalarType: getProtoScalarType(f.fieldType),
+		}
+
+		switch {
+		case f.slice:
+			pf.label = "repeated"
+		case f.stringMap:
+			pf.scalarType = fmt.Sprintf("map<string, %s>", pf.scalarType)
+		default:
+			pf.label = "optional"
+		}
+
+		if err := addName(pf.name, "field "+f.name); err != nil {
+			return nil, err
+		}
+
+		msg.fields = append(msg.fields, pf)
+	}
+
+	return msg, nil
+}
+
+// protoWriter writes the messages of a Protobuf schema, assigning the field numbers from the lock.
+type protoWriter struct {
+	oldLock protoLock
+	newLock protoLock
+	buf     bytes.Buffer
+}
+
+func (w *protoWriter) writeComment(indent, comment string) {
+	if comment == "" {
+		return
+	}
+
+	for _, line := range strings.Split(comment, "\n") {
+		w.buf.WriteString(strings.TrimRight(indent+"// "+line, " ") + "\n")
+	}
+}
+
+func (w *protoWriter) writeMessage(indent string, msg *protoMessage) {
+	fieldNames := make([]string, 0, len(msg.fields))
+	for _, f := range msg.fields {
+		fieldNames = append(fieldNames, f.name)
+	}
+
+	msgLock := w.oldLock.Messages[msg.fullName].assignNumbers(fieldNames)
+	w.newLock.Messages[msg.fullName] = msgLock
+
+	w.writeComment(indent, msg.comment)
+	fmt.Fprintf(&w.buf, "%smessage %s {\n", indent, msg.name)
+
+	fieldIndent := indent + "  "
+
+	// the declarations of the message are separated by blank lines
+	empty := true
+	separate := func() {
+		if !empty {
+			w.buf.WriteString("\n")
+		}
+		empty = false
+	}
+
+	if len(msgLock.Reserved) > 0 {
+		reserved := make([]string, 0, len(msgLock.Reserved))
+		for _, number := range msgLock.Reserved {
+			reserved = append(reserved, strconv.Itoa(number))
+		}
+
+		separate()
+		fmt.Fprintf(&w.buf, "%sreserved %s;\n", fieldIndent, strings.Join(reserved, ", "))
+	}
+
+	for _, f := range msg.fields {
+		if f.message != nil {
+			separate()
+			w.writeMessage(fieldIndent, f.message)
+		}
+	}
+
+	for _, f := range msg.fields {
+		separate()
+		w.writeComment(fieldIndent, f.comment)
+
+		fieldType := f.scalarType
+		if f.message != nil {
+			fieldType = f.message.name
+		}
+
+		if f.label != "" {
+			fieldType = f.label + " " + fieldType
+		}
+
+		fmt.Fprintf(&w.buf, "%s%s %s = %d;\n", fieldIndent, fieldType, f.name, msgLock.Fields[f.name])
+	}
+
+	fmt.Fprintf(&w.buf, "%s}\n", indent)
+}
+
+// generateProto generates the Protobuf schema of the record, using the field numbers of the lock.
+// It returns the lock with the field numbers of the generated schema.
+func generateProto(writer io.Writer, cfg protoGenConfig) (protoLock, error) {
+	msg, err := createProtoMessage(
+		cfg.record,
+		cfg.record,
+		cfg.record+" is the telemetry data for the product.",
+		cfg.fields,
+	)
+	if err != nil {
+		return protoLock{}, err
+	}
+
+	w := protoWriter{
+		oldLock: cfg.lock,
+		newLock: protoLock{Messages: make(map[string]protoLockMessage)},
+	}
+
+	w.buf.WriteString("// This is a generated file. DO NOT EDIT.\n")
+	w.buf.WriteString("// The field numbers are kept in the lock file of the schema, which must not be removed.\n\n")
+	w.buf.WriteString("syntax = \"proto3\";\n\n")
+	fmt.Fprintf(&w.buf, "package %s;\n\n", cfg.namespace)
+
+	w.writeMessage("", msg)
+
+	if _, err := writer.Write(w.buf.Bytes()); err != nil {
+		return protoLock{}, fmt.Errorf("failed to write Protobuf schema: %w", err)
+	}
+
+	return w.newLock, nil
+}
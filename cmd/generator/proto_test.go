@@ -0,0 +1,226 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"go/types"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestAssignNumbers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		lock       protoLockMessage
+		fieldNames []string
+		expected   protoLockMessage
+	}{
+		{
+			name:       "empty lock",
+			fieldNames: []string{"A", "B"},
+			expected: protoLockMessage{
+				Fields: map[string]int{"A": 1, "B": 2},
+			},
+		},
+		{
+			name: "added field",
+			lock: protoLockMessage{
+				Fields: map[string]int{"A": 1, "B": 2},
+			},
+			fieldNames: []string{"C", "A", "B"},
+			expected: protoLockMessage{
+				Fields: map[string]int{"A": 1, "B": 2, "C": 3},
+			},
+		},
+		{
+			name: "removed field",
+			lock: protoLockMessage{
+				Fields:   map[string]int{"A": 1, "B": 2, "C": 4},
+				Reserved: []int{3},
+			},
+			fieldNames: []string{"A"},
+			expected: protoLockMessage{
+				Fields:   map[string]int{"A": 1},
+				Reserved: []int{2, 3, 4},
+			},
+		},
+		{
+			name: "removed field added again",
+			lock: protoLockMessage{
+				Fields:   map[string]int{"A": 1},
+				Reserved: []int{2},
+			},
+			fieldNames: []string{"A", "B"},
+			expected: protoLockMessage{
+				Fields:   map[string]int{"A": 1, "B": 3},
+				Reserved: []int{2},
+			},
+		},
+		{
+			name: "numbers reserved by Protobuf",
+			lock: protoLockMessage{
+				Fields: map[string]int{"A": protoFirstReservedNumber - 1},
+			},
+			fieldNames: []string{"A", "B"},
+			expected: protoLockMessage{
+				Fields: map[string]int{"A": protoFirstReservedNumber - 1, "B": protoLastReservedNumber + 1},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(test.lock.assignNumbers(test.fieldNames)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestGenerateProto(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := protoGenConfig{
+		namespace: "gateway.nginx.org",
+		record:    "Data",
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+			{
+				docString: "Counts are counts.\nMore comments.",
+				name:      "Counts",
+				key:       "counts",
+				fieldType: types.Int32,
+				slice:     true,
+			},
+			{name: "Labels", fieldType: types.String, stringMap: true},
+			{
+				name:           "ClusterData",
+				embeddedStruct: true,
+				embeddedStructFields: []field{
+					{name: "Nodes", fieldType: types.Int, pointer: true},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	lock, err := generateProto(&buf, cfg)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(buf.String()).To(Equal(`// This is a generated file. DO NOT EDIT.
+// The field numbers are kept in the lock file of the schema, which must not be removed.
+
+syntax = "proto3";
+
+package gateway.nginx.org;
+
+// Data is the telemetry data for the product.
+message Data {
+  // ClusterData holds the fields of the embedded struct ClusterData.
+  message ClusterData {
+    optional int64 Nodes = 1;
+  }
+
+  // Name is a name.
+  optional string Name = 1;
+
+  // Counts are counts.
+  // More comments.
+  repeated int32 counts = 2;
+
+  map<string, string> Labels = 3;
+
+  // ClusterData holds the fields of the embedded struct ClusterData.
+  ClusterData cluster_data = 4;
+}
+`))
+	g.Expect(lock).To(Equal(protoLock{
+		Messages: map[string]protoLockMessage{
+			"Data":             {Fields: map[string]int{"Name": 1, "counts": 2, "Labels": 3, "cluster_data": 4}},
+			"Data.ClusterData": {Fields: map[string]int{"Nodes": 1}},
+		},
+	}))
+
+	// the numbers are kept when the fields change
+	cfg.lock = lock
+	cfg.fields = append([]field{{name: "Added", fieldType: types.Bool}}, cfg.fields[1:]...)
+
+	buf.Reset()
+
+	lock, err = generateProto(&buf, cfg)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(buf.String()).To(ContainSubstring("message Data {\n  reserved 1;\n"))
+	g.Expect(buf.String()).To(ContainSubstring("optional bool Added = 5;"))
+	g.Expect(buf.String()).To(ContainSubstring("repeated int32 counts = 2;"))
+	g.Expect(lock.Messages["Data"]).To(Equal(protoLockMessage{
+		Fields:   map[string]int{"Added": 5, "counts": 2, "Labels": 3, "cluster_data": 4},
+		Reserved: []int{1},
+	}))
+}
+
+func TestGenerateProtoNameConflict(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	cfg := protoGenConfig{
+		namespace: "gateway.nginx.org",
+		record:    "Data",
+		fields: []field{
+			{name: "ClusterData", key: "cluster_data", fieldType: types.String},
+			{name: "ClusterData", embeddedStruct: true},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	_, err := generateProto(&buf, cfg)
+
+	g.Expect(err).To(MatchError(
+		"message Data: name cluster_data of field ClusterData is already used by field ClusterData",
+	))
+}
+
+func TestLoadProtoLock(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data.proto.lock")
+
+	lock, err := loadProtoLock(path)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(lock).To(Equal(protoLock{}))
+
+	expectedLock := protoLock{
+		Messages: map[string]protoLockMessage{
+			"Data": {Fields: map[string]int{"Name": 1}, Reserved: []int{2}},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(encodeProtoLock(&buf, expectedLock)).To(Succeed())
+	g.Expect(os.WriteFile(path, buf.Bytes(), 0o600)).To(Succeed())
+
+	lock, err = loadProtoLock(path)
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(lock).To(Equal(expectedLock))
+
+	g.Expect(os.WriteFile(path, []byte("{"), 0o600)).To(Succeed())
+
+	_, err = loadProtoLock(path)
+
+	g.Expect(err).To(MatchError(ContainSubstring("failed to decode lock file")))
+}
@@ -3,35 +3,50 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"go/types"
 	"io"
-	"text/template"
+	"strings"
 )
 
 const schemeTemplate = `@namespace("{{ .Namespace }}") protocol {{ .Protocol }} {
+{{- range .Enums }}
+	enum {{ .Name }} {
+		{{ join .Symbols ", " }}
+	}
+{{ end }}
 	/** {{ .Record }} is the telemetry data for the product. */
-	@df_datatype("{{ .DataFabricDataType }}") record {{ .Record }} {
-		/** The field that identifies what type of data this is. */
-		string dataType;
-		/** The time the event occurred */
-		long eventTime;
-		/** The time our edge ingested the event */
-		long ingestTime;
-
-		{{ range .Fields }}
+	{{ range .Annotations }}@{{ .Name }}({{ jsonString .Value }}) {{ end }}record {{ .Record }} {
+		{{- range .EnvelopeFields }}
+		{{- if .Doc }}
+		/** {{ .Doc }} */
+		{{- end }}
+		{{ .Type }} {{ .Name }};
+		{{- end }}
+
+		{{ range $i, $group := .Groups }}
+		{{- if or $group.Embedded (gt $i 0) }}
+		// {{ if $group.Embedded }}Fields embedded from{{ else }}Fields of{{ end }} {{ $group.Source }}.
+		{{- range commentLines $group.Comment }}
+		//{{ if . }} {{ . }}{{ end }}
+		{{- end }}
+		{{ end }}
+		{{- range $group.Fields }}
 		/** {{ .Comment }} */
 		{{ .Type }} {{ .Name }} = null;
 		{{ end }}
+		{{- end }}
 	}
 }
 `
 
 func getAvroPrimitiveType(kind types.BasicKind) string {
 	switch kind {
-	case types.Int64:
+	case types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.Uint8, types.Uint16, types.Uint32:
 		return "long"
-	case types.Float64:
+	case types.Float32, types.Float64:
 		return "double"
 	case types.String:
 		return "string"
@@ -47,7 +62,29 @@ type schemeGen struct {
 	Protocol           string
 	DataFabricDataType string
 	Record             string
-	Fields             []schemeField
+	// Annotations are the annotations of the record, including the data fabric data type by default.
+	Annotations []schemeAnnotation
+	// EnvelopeFields are the fields of the envelope, which precede the fields of the data.
+	EnvelopeFields []envelopeField
+	Enums          []schemeEnum
+	// Groups are the fields of the record, grouped by the struct that declares them.
+	Groups []schemeFieldGroup
+}
+
+// schemeFieldGroup is a run of consecutive fields of the record that are declared by the same struct.
+type schemeFieldGroup struct {
+	// Source is the name of the struct that declares the fields, qualified by its package for embedded structs.
+	Source string
+	// Comment is the doc string of the embedded struct.
+	Comment string
+	Fields  []schemeField
+	// Embedded is true when the fields are declared by an embedded struct.
+	Embedded bool
+}
+
+type schemeEnum struct {
+	Name    string
+	Symbols []string
 }
 
 type schemeField struct {
@@ -61,49 +98,219 @@ type schemeGenConfig struct {
 	protocol           string
 	dataFabricDataType string
 	record             string
-	fields             []field
+	// template is the custom template of the scheme, which receives a schemeGen. Empty means the default template.
+	// It doesn't change the Avro JSON schema or the compatibility checks, which use the fields.
+	template string
+	fields   []field
+	// envelope is the envelope of the record.
+	envelope schemeEnvelope
 }
 
-func generateScheme(writer io.Writer, cfg schemeGenConfig) error {
-	var schemeFields []schemeField
+// isAvroEnum returns true if the field value is of a named string type with typed constants, which are all valid
+// Avro enum symbols. The empty string is never a symbol, so the zero value of an enum field is omitted from its
+// attributes. Only typed string constants are collected, so named types of other kinds, like int types that
+// implement fmt.Stringer, are not enums: their values are exported as plain strings.
+func isAvroEnum(f field) bool {
+	if f.named == nil || f.named.stringer || f.fieldType != types.String || len(f.named.enumValues) == 0 {
+		return false
+	}
+
+	for _, v := range f.named.enumValues {
+		if !avroNameRegexp.MatchString(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// avroTimestampTypes are the Avro timestamp logical types for the time precisions.
+// There is no logical type for timestamps in seconds, so they're just long.
+var avroTimestampTypes = map[timePrecision]string{
+	timePrecisionSeconds:      "long",
+	timePrecisionMilliseconds: "timestamp_ms",
+	timePrecisionMicroseconds: `@logicalType("timestamp-micros") long`,
+	timePrecisionNanoseconds:  `@logicalType("timestamp-nanos") long`,
+}
+
+// getAvroType returns the Avro type of the field value: either an enum, a timestamp or a primitive type.
+// Durations are long in the precision of the field.
+func getAvroType(f field) string {
+	if isAvroEnum(f) {
+		return f.named.name
+	}
 
-	var createSchemeFields func([]field)
-	createSchemeFields = func(fields []field) {
+	if f.timeKind == timeKindTimestamp {
+		return avroTimestampTypes[f.timePrecision]
+	}
+
+	return getAvroPrimitiveType(f.fieldType)
+}
+
+// getAvroFieldType returns the Avro type of the field: an array or a map of its value type, or its value type.
+func getAvroFieldType(f field) string {
+	switch {
+	case f.slice:
+		return fmt.Sprintf("array<%s>", getAvroType(f))
+	case f.stringMap:
+		return fmt.Sprintf("map<%s>", getAvroType(f))
+	default:
+		return getAvroType(f)
+	}
+}
+
+// getNullableAvroType returns the nullable version of the Avro type.
+func getNullableAvroType(avroType string) string {
+	// the ? shorthand can't be used for types with annotations
+	if strings.HasPrefix(avroType, "@") {
+		return fmt.Sprintf("union {null, %s}", avroType)
+	}
+
+	return avroType + "?"
+}
+
+// getAvroEnums returns the Avro enums of the fields, including the fields of the embedded structs, in the order
+// of their first use. Enums with the same name must be declared in the same package.
+func getAvroEnums(fields []field) ([]schemeEnum, error) {
+	var schemeEnums []schemeEnum
+
+	enumPackages := make(map[string]string)
+
+	var collectEnums func([]field) error
+	collectEnums = func(fields []field) error {
 		for _, f := range fields {
+			if f.embeddedStruct {
+				if err := collectEnums(f.embeddedStructFields); err != nil {
+					return err
+				}
+
+				continue
+			}
+
+			if !isAvroEnum(f) {
+				continue
+			}
+
+			pkg, exists := enumPackages[f.named.name]
+
 			switch {
-			case f.slice:
-				schemeFields = append(schemeFields, schemeField{
-					Comment: f.docString,
-					Type:    fmt.Sprintf("union {null, array<%s>}", getAvroPrimitiveType(f.fieldType)),
-					Name:    f.name,
-				})
-			case f.embeddedStruct:
-				createSchemeFields(f.embeddedStructFields)
-			default:
-				schemeFields = append(schemeFields, schemeField{
-					Comment: f.docString,
-					Type:    getAvroPrimitiveType(f.fieldType) + "?",
-					Name:    f.name,
+			case !exists:
+				enumPackages[f.named.name] = f.named.packagePath
+				schemeEnums = append(schemeEnums, schemeEnum{
+					Name:    f.named.name,
+					Symbols: f.named.enumValues,
 				})
+			case pkg != f.named.packagePath:
+				return fmt.Errorf("enum %s is declared in both %s and %s", f.named.name, pkg, f.named.packagePath)
+			}
+		}
+
+		return nil
+	}
+
+	if err := collectEnums(fields); err != nil {
+		return nil, err
+	}
+
+	return schemeEnums, nil
+}
+
+// createSchemeField creates the schemeField of the field, which is not an embedded struct.
+func createSchemeField(f field) schemeField {
+	avroType := getNullableAvroType(getAvroType(f))
+	if f.slice || f.stringMap {
+		avroType = fmt.Sprintf("union {null, %s}", getAvroFieldType(f))
+	}
+
+	return schemeField{
+		Comment: f.docString,
+		Type:    avroType,
+		Name:    getAvroName(f.attributeKey()),
+	}
+}
+
+// groupSchemeFields returns the fields of the record grouped by the struct that declares them, where the fields
+// of the embedded structs are flattened in the order of their declaration.
+func groupSchemeFields(record string, fields []field) []schemeFieldGroup {
+	var groups []schemeFieldGroup
+
+	var addGroups func(schemeFieldGroup, []field)
+	addGroups = func(group schemeFieldGroup, fields []field) {
+		for _, f := range fields {
+			if !f.embeddedStruct {
+				group.Fields = append(group.Fields, createSchemeField(f))
+				continue
 			}
+
+			if len(group.Fields) > 0 {
+				groups = append(groups, group)
+			}
+
+			addGroups(schemeFieldGroup{
+				Source:   f.embeddedStructName(),
+				Comment:  f.embeddedStructDocString,
+				Embedded: true,
+			}, f.embeddedStructFields)
+
+			// the fields after the embedded struct continue the group without repeating its comment
+			group.Comment, group.Fields = "", nil
+		}
+
+		if len(group.Fields) > 0 {
+			groups = append(groups, group)
 		}
 	}
 
-	createSchemeFields(cfg.fields)
+	addGroups(schemeFieldGroup{Source: record}, fields)
+
+	return groups
+}
+
+// getCommentLines splits the comment into its lines. An empty comment has no lines.
+func getCommentLines(comment string) []string {
+	if comment == "" {
+		return nil
+	}
+
+	return strings.Split(comment, "\n")
+}
+
+// getJSONString returns the value as a JSON string, which is the format of the values of the Avro IDL annotations.
+func getJSONString(value string) (string, error) {
+	var buf bytes.Buffer
+
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func generateScheme(writer io.Writer, cfg schemeGenConfig) error {
+	schemeEnums, err := getAvroEnums(cfg.fields)
+	if err != nil {
+		return err
+	}
 
 	sg := schemeGen{
 		Namespace:          cfg.namespace,
 		Protocol:           cfg.protocol,
 		DataFabricDataType: cfg.dataFabricDataType,
 		Record:             cfg.record,
-		Fields:             schemeFields,
+		Annotations:        cfg.envelope.getAnnotations(cfg.dataFabricDataType),
+		EnvelopeFields:     cfg.envelope.getFields(),
+		Enums:              schemeEnums,
 	}
 
-	funcMap := template.FuncMap{
-		"getAttributeType": getAttributeType,
-	}
+	sg.Groups = groupSchemeFields(cfg.record, cfg.fields)
 
-	tmpl := template.Must(template.New("scheme").Funcs(funcMap).Parse(schemeTemplate))
+	tmpl, err := parseTemplate("scheme", schemeTemplate, cfg.template)
+	if err != nil {
+		return err
+	}
 
 	if err := tmpl.Execute(writer, sg); err != nil {
 		return fmt.Errorf("failed to execute template: %w", err)
@@ -4,6 +4,8 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"go/types"
 	"testing"
 
 	. "github.com/onsi/gomega"
@@ -17,7 +19,7 @@ func TestGenerateScheme(t *testing.T) {
 
 	parseCfg := parsingConfig{
 		pkgName:     "tests",
-		typeName:    "Data",
+		typeNames:   []string{"Data"},
 		loadPattern: "github.com/nginx/telemetry-exporter/cmd/generator/tests",
 		buildFlags:  []string{"-tags=generator"},
 	}
@@ -34,11 +36,309 @@ func TestGenerateScheme(t *testing.T) {
 		namespace:          "gateway.nginx.org",
 		protocol:           "avro",
 		dataFabricDataType: "telemetry",
-		record:             parseCfg.typeName,
-		fields:             pResult.fields,
+		record:             pResult.types[0].name,
+		fields:             pResult.types[0].fields,
 	}
 
 	g.Expect(generateScheme(&buf, schemeCfg)).To(Succeed())
 
 	g.Expect(buf.Bytes()).ToNot(BeEmpty())
 }
+
+func TestGenerateSchemeEnumConflict(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "gateway.nginx.org",
+		protocol:           "avro",
+		dataFabricDataType: "telemetry",
+		record:             "Data",
+		fields: []field{
+			{
+				docString: "Platform is a platform.",
+				name:      "Platform",
+				fieldType: types.String,
+				named: &namedType{
+					packagePath: "example.com/a",
+					name:        "Platform",
+					enumValues:  []string{"aws"},
+				},
+			},
+			{
+				docString: "OtherPlatform is a platform from another package.",
+				name:      "OtherPlatform",
+				fieldType: types.String,
+				named: &namedType{
+					packagePath: "example.com/b",
+					name:        "Platform",
+					enumValues:  []string{"gcp"},
+				},
+			},
+		},
+	}
+
+	err := generateScheme(&bytes.Buffer{}, schemeCfg)
+
+	g.Expect(err).To(MatchError("enum Platform is declared in both example.com/a and example.com/b"))
+}
+
+func TestGenerateSchemeGroupsEmbeddedFields(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "gateway.nginx.org",
+		protocol:           "avro",
+		dataFabricDataType: "telemetry",
+		record:             "Data",
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+			{
+				name:                    "ClusterData",
+				embeddedStruct:          true,
+				embeddedStructPackage:   "example.com/cluster",
+				embeddedStructDocString: "ClusterData is the data of the cluster.\n\nIt is reported by the controller.",
+				embeddedStructFields: []field{
+					{docString: "Nodes is the number of nodes.", name: "Nodes", fieldType: types.Int},
+					{
+						name:                  "NodeData",
+						embeddedStruct:        true,
+						embeddedStructPackage: "example.com/node",
+						embeddedStructFields: []field{
+							{docString: "CPUs is the number of CPUs.", name: "CPUs", fieldType: types.Int},
+						},
+					},
+					{docString: "Pods is the number of pods.", name: "Pods", fieldType: types.Int},
+				},
+			},
+			{docString: "Count is a count.", name: "Count", fieldType: types.Int},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateScheme(&buf, schemeCfg)).To(Succeed())
+
+	expectedFields := `		long ingestTime;
+
+		
+		/** Name is a name. */
+		string? Name = null;
+		
+		// Fields embedded from cluster.ClusterData.
+		// ClusterData is the data of the cluster.
+		//
+		// It is reported by the controller.
+		
+		/** Nodes is the number of nodes. */
+		long? Nodes = null;
+		
+		// Fields embedded from node.NodeData.
+		
+		/** CPUs is the number of CPUs. */
+		long? CPUs = null;
+		
+		// Fields embedded from cluster.ClusterData.
+		
+		/** Pods is the number of pods. */
+		long? Pods = null;
+		
+		// Fields of Data.
+		
+		/** Count is a count. */
+		long? Count = null;
+		
+	}
+}
+`
+
+	g.Expect(buf.String()).To(HaveSuffix(expectedFields))
+}
+
+func TestGenerateSchemeCustomEnvelope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "example.com",
+		protocol:           "Telemetry",
+		dataFabricDataType: "product-telemetry",
+		record:             "Data",
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+		},
+		envelope: schemeEnvelope{
+			DataTypeAnnotation: "datatype",
+			Annotations:        map[string]string{"retention": `90 "days"`},
+			Fields: []envelopeField{
+				{Name: "timestamp", Type: "long", Doc: "The time of the record."},
+				{Name: "source", Type: "string"},
+			},
+		},
+	}
+
+	var idlBuf, avscBuf bytes.Buffer
+
+	g.Expect(generateScheme(&idlBuf, schemeCfg)).To(Succeed())
+	g.Expect(generateAvsc(&avscBuf, schemeCfg)).To(Succeed())
+
+	g.Expect(idlBuf.String()).To(ContainSubstring(`	/** Data is the telemetry data for the product. */
+	@datatype("product-telemetry") @retention("90 \"days\"") record Data {
+		/** The time of the record. */
+		long timestamp;
+		string source;
+
+`))
+
+	avscRecord, err := parseAvsc(avscBuf.Bytes())
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// the envelope fields come first, like in the Avro IDL scheme
+	fieldNames := make([]string, 0, len(avscRecord.fields))
+	for _, f := range avscRecord.fields {
+		fieldNames = append(fieldNames, f.name)
+	}
+
+	g.Expect(fieldNames).To(Equal([]string{"timestamp", "source", "Name"}))
+
+	var record map[string]any
+
+	g.Expect(json.Unmarshal(avscBuf.Bytes(), &record)).To(Succeed())
+
+	g.Expect(record).To(HaveKeyWithValue("datatype", "product-telemetry"))
+	g.Expect(record).To(HaveKeyWithValue("retention", `90 "days"`))
+	g.Expect(record).ToNot(HaveKey("df_datatype"))
+	g.Expect(record["fields"]).To(HaveLen(3))
+
+	// the properties are between the doc and the fields
+	g.Expect(avscBuf.String()).To(MatchRegexp(`(?s)"doc".*"datatype".*"retention".*"fields"`))
+}
+
+func TestGenerateSchemeWithoutEnvelope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace: "example.com",
+		protocol:  "Telemetry",
+		record:    "Data",
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+		},
+		envelope: schemeEnvelope{DataTypeAnnotation: "-", Fields: []envelopeField{}},
+	}
+
+	g.Expect(validateSchemeGenConfig(schemeCfg)).To(Succeed())
+
+	var buf bytes.Buffer
+
+	g.Expect(generateScheme(&buf, schemeCfg)).To(Succeed())
+
+	g.Expect(buf.String()).To(ContainSubstring(`	/** Data is the telemetry data for the product. */
+	record Data {
+
+		
+		/** Name is a name. */
+		string? Name = null;
+`))
+}
+
+func TestValidateSchemeGenConfigEnvelope(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace: "example.com",
+		protocol:  "Telemetry",
+		record:    "Data",
+		fields: []field{
+			{docString: "Source is the source.", name: "Source", key: "source", fieldType: types.String},
+		},
+	}
+
+	g.Expect(validateSchemeGenConfig(schemeCfg)).To(MatchError(ContainSubstring("data fabric data type is required")))
+
+	schemeCfg.dataFabricDataType = "telemetry"
+	schemeCfg.envelope = schemeEnvelope{Fields: []envelopeField{{Name: "source", Type: "string"}}}
+
+	g.Expect(validateSchemeGenConfig(schemeCfg)).To(MatchError("field Source: Avro name source is used by the envelope"))
+}
+
+func TestGetAvroType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name             string
+		expected         string
+		expectedNullable string
+		field            field
+	}{
+		{
+			name:             "long",
+			field:            field{fieldType: types.Int32},
+			expected:         "long",
+			expectedNullable: "long?",
+		},
+		{
+			name: "enum",
+			field: field{
+				fieldType: types.String,
+				named:     &namedType{name: "Platform", enumValues: []string{"aws", "gcp"}},
+			},
+			expected:         "Platform",
+			expectedNullable: "Platform?",
+		},
+		{
+			name: "named string with invalid enum symbols",
+			field: field{
+				fieldType: types.String,
+				named:     &namedType{name: "Platform", enumValues: []string{"aws-eks"}},
+			},
+			expected:         "string",
+			expectedNullable: "string?",
+		},
+		{
+			name: "timestamp in milliseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionMilliseconds,
+			},
+			expected:         "timestamp_ms",
+			expectedNullable: "timestamp_ms?",
+		},
+		{
+			name: "timestamp in microseconds",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindTimestamp,
+				timePrecision: timePrecisionMicroseconds,
+			},
+			expected:         `@logicalType("timestamp-micros") long`,
+			expectedNullable: `union {null, @logicalType("timestamp-micros") long}`,
+		},
+		{
+			name: "duration",
+			field: field{
+				fieldType:     types.Int64,
+				timeKind:      timeKindDuration,
+				timePrecision: timePrecisionMilliseconds,
+			},
+			expected:         "long",
+			expectedNullable: "long?",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			avroType := getAvroType(test.field)
+
+			g.Expect(avroType).To(Equal(test.expected))
+			g.Expect(getNullableAvroType(avroType)).To(Equal(test.expectedNullable))
+		})
+	}
+}
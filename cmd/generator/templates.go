@@ -0,0 +1,147 @@
+//go:build generator
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// templateFunc is a helper function of the code and scheme templates.
+type templateFunc struct {
+	fn   any
+	name string
+	// description is the description of the function in the usage.
+	description string
+}
+
+// templateFuncs are the helper functions of the code and scheme templates, including the custom templates set by
+// the -code-template and -scheme-template flags.
+//
+// A custom scheme template only changes the .avdl scheme: the Avro JSON schema and the compat command use the
+// fields of the types instead, so they don't follow the changes of the template.
+var templateFuncs = []templateFunc{
+	{
+		name:        "join",
+		fn:          strings.Join,
+		description: "joins a list of strings with a separator, like strings.Join",
+	},
+	{
+		name:        "commentLines",
+		fn:          getCommentLines,
+		description: "splits a comment into its lines; an empty comment has no lines",
+	},
+	{
+		name:        "jsonString",
+		fn:          getJSONString,
+		description: "returns a string as a JSON string, like the values of the Avro IDL annotations",
+	},
+	{
+		name:        "quote",
+		fn:          strconv.Quote,
+		description: "returns a string as a Go string literal, like strconv.Quote",
+	},
+	{
+		name:        "lower",
+		fn:          strings.ToLower,
+		description: "returns a string in lower case, like strings.ToLower",
+	},
+	{
+		name:        "upper",
+		fn:          strings.ToUpper,
+		description: "returns a string in upper case, like strings.ToUpper",
+	},
+}
+
+// defaultTemplateName is the name of the default template, which a custom template can execute to wrap it,
+// like {{ template "default" . }}.
+const defaultTemplateName = "default"
+
+// templateField is a field of the data of a template.
+type templateField struct {
+	name        string
+	description string
+}
+
+// codeTemplateFields are the fields of the data of the code template, which is a codeGen.
+var codeTemplateFields = []templateField{
+	{name: "PackageName", description: "the name of the package of the generated code"},
+	{name: "TelemetryPackagePath", description: "the import path of the telemetry package"},
+	{name: "TelemetryPackageAlias", description: "the alias of the import of the telemetry package, if any"},
+	{name: "ExportablePackagePrefix", description: "the qualifier of the telemetry package, like telemetry."},
+	{name: "BuildTags", description: "the build constraint expression of the generated code, if any"},
+	{name: "StandardImports", description: "the imported packages of the standard library"},
+	{name: "Imports", description: "the other imported packages, except for the telemetry package"},
+	{
+		name: "Types",
+		description: "the types, with their StructName, SchemeDataType, DataTypeKey, Fields, FromAttributesFields, " +
+			"ValidationRules and TelemetryFields",
+	},
+}
+
+// schemeTemplateFields are the fields of the data of the scheme template, which is a schemeGen.
+var schemeTemplateFields = []templateField{
+	{name: "Namespace", description: "the namespace of the scheme"},
+	{name: "Protocol", description: "the protocol of the scheme"},
+	{name: "DataFabricDataType", description: "the data fabric data type of the record"},
+	{name: "Record", description: "the name of the record"},
+	{name: "Annotations", description: "the annotations of the record, with their Name and Value"},
+	{name: "EnvelopeFields", description: "the fields of the envelope, with their Name, Type and Doc"},
+	{name: "Enums", description: "the enums of the fields, with their Name and Symbols"},
+	{
+		name: "Groups",
+		description: "the fields of the record grouped by the struct that declares them, with their Source, Comment, " +
+			"Embedded and Fields, which have a Comment, Type and Name",
+	},
+}
+
+// getTemplateFuncMap returns the helper functions of the templates.
+func getTemplateFuncMap() template.FuncMap {
+	funcMap := make(template.FuncMap, len(templateFuncs))
+	for _, f := range templateFuncs {
+		funcMap[f.name] = f.fn
+	}
+
+	return funcMap
+}
+
+// parseTemplate parses the custom template, or the default template when the custom template is empty,
+// with the helper functions. The default template is also defined as the default named template, so that the custom
+// template can wrap it.
+func parseTemplate(name, defaultText, customText string) (*template.Template, error) {
+	tmpl := template.New(name).Funcs(getTemplateFuncMap())
+
+	if _, err := tmpl.New(defaultTemplateName).Parse(defaultText); err != nil {
+		return nil, fmt.Errorf("failed to parse default %s template: %w", name, err)
+	}
+
+	mainText := withDefault(customText, fmt.Sprintf("{{ template %q . }}", defaultTemplateName))
+
+	if _, err := tmpl.Parse(mainText); err != nil {
+		return nil, fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// loadTemplate loads a custom template from a file. An empty path means the default template, so an empty
+// template is returned.
+func loadTemplate(name, path string) (string, error) {
+	if path == "" {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(path) //nolint:gosec // the path of the template is set by the user
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s template: %w", name, err)
+	}
+
+	if len(strings.TrimSpace(string(content))) == 0 {
+		return "", fmt.Errorf("%s template %s is empty", name, path)
+	}
+
+	return string(content), nil
+}
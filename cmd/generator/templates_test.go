@@ -0,0 +1,316 @@
+//go:build generator
+
+package main
+
+import (
+	"bytes"
+	"go/types"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGenerateCodeCustomTemplate(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	codeCfg := codeGenConfig{
+		packagePath: "example.com/telemetry",
+		template: `// Copyright Example Corp.
+
+package {{ .PackageName }}
+{{ range .Types }}
+// Reset resets {{ .StructName }}.
+func (d *{{ .StructName }}) Reset() {
+		*d = {{ .StructName }}{}
+}
+
+const {{ lower .StructName }}Keys = {{ range .TelemetryFields }}{{ quote .Key }} + {{ end }}""
+{{ end }}`,
+		types: []codeGenType{
+			{
+				typeName: "Data",
+				fields: []field{
+					{docString: "Name is a name.", name: "Name", fieldType: types.String},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateCode(&buf, codeCfg)).To(Succeed())
+
+	// the code of the custom template is formatted
+	g.Expect(buf.String()).To(Equal(`// Copyright Example Corp.
+
+package telemetry
+
+// Reset resets Data.
+func (d *Data) Reset() {
+	*d = Data{}
+}
+
+const dataKeys = "Name" + ""
+`))
+}
+
+func TestGenerateSchemeCustomTemplate(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "example.com",
+		protocol:           "Telemetry",
+		dataFabricDataType: "product-telemetry",
+		record:             "Data",
+		template: `// Copyright Example Corp.
+{{ range .Groups }}{{ range .Fields }}{{ upper .Name }} {{ .Type }}
+{{ end }}{{ end }}{{ range .Annotations }}{{ .Name }}={{ jsonString .Value }}{{ end }}
+`,
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+			{docString: "Count is a count.", name: "Count", fieldType: types.Int},
+		},
+	}
+
+	var buf bytes.Buffer
+
+	g.Expect(generateScheme(&buf, schemeCfg)).To(Succeed())
+
+	g.Expect(buf.String()).To(Equal(`// Copyright Example Corp.
+NAME string?
+COUNT long?
+df_datatype="product-telemetry"
+`))
+}
+
+func TestGenerateCustomTemplateWrappingDefault(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	schemeCfg := schemeGenConfig{
+		namespace:          "example.com",
+		protocol:           "Telemetry",
+		dataFabricDataType: "product-telemetry",
+		record:             "Data",
+		fields: []field{
+			{docString: "Name is a name.", name: "Name", fieldType: types.String},
+		},
+	}
+
+	var defaultBuf bytes.Buffer
+
+	g.Expect(generateScheme(&defaultBuf, schemeCfg)).To(Succeed())
+
+	schemeCfg.template = "// Copyright Example Corp.\n\n{{ template \"default\" . }}"
+
+	var buf bytes.Buffer
+
+	g.Expect(generateScheme(&buf, schemeCfg)).To(Succeed())
+	g.Expect(buf.String()).To(Equal("// Copyright Example Corp.\n\n" + defaultBuf.String()))
+
+	codeCfg := codeGenConfig{
+		packagePath: "example.com/telemetry",
+		template:    "// Copyright Example Corp.\n\n{{ template \"default\" . }}",
+		types: []codeGenType{
+			{
+				typeName: "Data",
+				fields: []field{
+					{docString: "Name is a name.", name: "Name", fieldType: types.String},
+				},
+			},
+		},
+	}
+
+	buf.Reset()
+
+	g.Expect(generateCode(&buf, codeCfg)).To(Succeed())
+	g.Expect(buf.String()).To(HavePrefix("// Copyright Example Corp.\n\n"))
+	g.Expect(buf.String()).To(ContainSubstring("func (d *Data) Attributes() []attribute.KeyValue {"))
+}
+
+func TestTemplateFields(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		data   any
+		name   string
+		fields []templateField
+	}{
+		{
+			name:   "code",
+			data:   codeGen{},
+			fields: codeTemplateFields,
+		},
+		{
+			name:   "scheme",
+			data:   schemeGen{},
+			fields: schemeTemplateFields,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			// the usage documents every field of the data of the template
+			var expected []string
+			for _, f := range reflect.VisibleFields(reflect.TypeOf(test.data)) {
+				expected = append(expected, f.Name)
+			}
+
+			names := make([]string, 0, len(test.fields))
+			for _, f := range test.fields {
+				names = append(names, f.name)
+			}
+
+			g.Expect(names).To(Equal(expected))
+		})
+	}
+}
+
+func TestGenerateCustomTemplateErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		generate func(template string) error
+		name     string
+		template string
+		expErr   string
+	}{
+		{
+			name:     "invalid code template",
+			template: "package {{ .PackageName ",
+			generate: func(template string) error {
+				return generateCode(&bytes.Buffer{}, codeGenConfig{packagePath: "example.com/telemetry", template: template})
+			},
+			expErr: "failed to parse code template",
+		},
+		{
+			name:     "unknown code template function",
+			template: "package {{ camel .PackageName }}",
+			generate: func(template string) error {
+				return generateCode(&bytes.Buffer{}, codeGenConfig{packagePath: "example.com/telemetry", template: template})
+			},
+			expErr: `function "camel" not defined`,
+		},
+		{
+			name:     "invalid code",
+			template: "package {{ .PackageName }}\n\nfunc {",
+			generate: func(template string) error {
+				return generateCode(&bytes.Buffer{}, codeGenConfig{packagePath: "example.com/telemetry", template: template})
+			},
+			expErr: "generated code is not valid Go",
+		},
+		{
+			name:     "invalid scheme template",
+			template: "{{ range .Groups }}",
+			generate: func(template string) error {
+				return generateScheme(&bytes.Buffer{}, schemeGenConfig{record: "Data", template: template})
+			},
+			expErr: "failed to parse scheme template",
+		},
+		{
+			name:     "unknown scheme model field",
+			template: "{{ .Fields }}",
+			generate: func(template string) error {
+				return generateScheme(&bytes.Buffer{}, schemeGenConfig{record: "Data", template: template})
+			},
+			expErr: "failed to execute template",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			err := test.generate(test.template)
+
+			g.Expect(err).To(HaveOccurred())
+			g.Expect(err.Error()).To(ContainSubstring(test.expErr))
+		})
+	}
+}
+
+func TestLoadTemplate(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	validPath := filepath.Join(dir, "code.tmpl")
+	if err := os.WriteFile(validPath, []byte("package {{ .PackageName }}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	emptyPath := filepath.Join(dir, "empty.tmpl")
+	if err := os.WriteFile(emptyPath, []byte("\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		expTemplate string
+		expErr      string
+	}{
+		{
+			name:        "default template",
+			path:        "",
+			expTemplate: "",
+		},
+		{
+			name:        "custom template",
+			path:        validPath,
+			expTemplate: "package {{ .PackageName }}\n",
+		},
+		{
+			name:   "missing file",
+			path:   filepath.Join(dir, "missing.tmpl"),
+			expErr: "failed to read code template",
+		},
+		{
+			name:   "empty file",
+			path:   emptyPath,
+			expErr: "is empty",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			template, err := loadTemplate("code", test.path)
+
+			if test.expErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(test.expErr))
+
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(template).To(Equal(test.expTemplate))
+		})
+	}
+}
+
+func TestGetTemplateFuncMap(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	funcMap := getTemplateFuncMap()
+
+	g.Expect(funcMap).To(HaveLen(len(templateFuncs)))
+
+	for _, f := range templateFuncs {
+		g.Expect(funcMap).To(HaveKey(f.name))
+		g.Expect(f.description).ToNot(BeEmpty())
+	}
+}
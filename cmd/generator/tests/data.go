@@ -2,16 +2,27 @@
 
 package tests
 
-import "github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests"
+import (
+	"time"
+
+	"github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests"
+)
 
 // Data includes a field of each supported data type.
 // We use this struct to test the generation of code and scheme.
 // We also use it to test that the generated code compiles and runs as expected.
+// The settings of the generation are set by the markers.
+//
+// +telemetry:build-tags=generator
+// +telemetry:scheme:protocol=NGFProductTelemetry
+// +telemetry:scheme:datatype=ngf-product-telemetry
 //
-//go:generate go run -tags generator github.com/nginx/telemetry-exporter/cmd/generator -type=Data -build-tags=generator -scheme -scheme-protocol=NGFProductTelemetry -scheme-df-datatype=ngf-product-telemetry
+//go:generate go run -tags generator github.com/nginx/telemetry-exporter/cmd/generator -type=Data -json-schema -avsc -proto -docs
 //nolint:govet // Disable fieldalignment linter (part of govet), to control the order of fields for better readability.
 type Data struct {
 	// SomeString is a string field.
+	//
+	// +telemetry:required
 	SomeString string
 	/* SomeInt is an int64 field. */
 	SomeInt int64
@@ -23,13 +34,80 @@ type Data struct {
 	/*
 		SomeStrings is a slice of strings.
 	*/
-	SomeStrings []string
+	SomeStrings []string `telemetry:",maxItems=5"`
 	// SomeInts is a slice of int64.
 	SomeInts []int64
 	// SomeFloats is a slice of float64.
 	SomeFloats []float64
 	// SomeBools is a slice of bool.
 	SomeBools []bool
+	// SomeStringMap is a map of strings.
+	SomeStringMap map[string]string
+	// SomeIntMap is a map of int64.
+	// +telemetry:maxItems=10
+	SomeIntMap map[string]int64
+	// SomeNativeInt is an int field.
+	SomeNativeInt int
+	// SomeInt8 is an int8 field.
+	// +telemetry:min=-10
+	// +telemetry:max=10
+	SomeInt8 int8
+	// SomeInt16 is an int16 field.
+	SomeInt16 int16
+	// SomeInt32 is an int32 field.
+	SomeInt32 int32
+	// SomeUint8 is a uint8 field.
+	SomeUint8 uint8
+	// SomeUint16 is a uint16 field.
+	SomeUint16 uint16
+	// SomeUint32 is a uint32 field.
+	SomeUint32 uint32
+	// SomeFloat32 is a float32 field.
+	SomeFloat32 float32
+	// SomeInt32s is a slice of int32.
+	SomeInt32s []int32
+	// SomeFloat32s is a slice of float32.
+	SomeFloat32s []float32
+	// SomeUint32Map is a map of uint32.
+	SomeUint32Map map[string]uint32
+	// SomePlatform is a named string field.
+	SomePlatform Platform
+	// SomeLevel is a fmt.Stringer field.
+	SomeLevel Level
+	// SomeCount is a named int32 field.
+	SomeCount Count
+	// SomePlatforms is a slice of a named string type.
+	SomePlatforms []Platform
+	// SomeLevelMap is a map of a fmt.Stringer type.
+	SomeLevelMap map[string]Level
+	// SomeStringPointer is a pointer to a string.
+	SomeStringPointer *string
+	// SomeIntPointer is a pointer to an int64.
+	SomeIntPointer *int64
+	// SomeFloatPointer is a pointer to a float64.
+	SomeFloatPointer *float64
+	// SomeBoolPointer is a pointer to a bool.
+	SomeBoolPointer *bool
+	// SomeCountPointer is a pointer to a named int32 type.
+	SomeCountPointer *Count `telemetry:",min=1"`
+	// SomeLevelPointer is a pointer to a fmt.Stringer type.
+	SomeLevelPointer *Level
+	// SomeTime is a time.Time field.
+	SomeTime time.Time
+	// SomeDuration is a time.Duration field.
+	SomeDuration time.Duration
+	// SomeTimePointer is a pointer to a time.Time.
+	SomeTimePointer *time.Time
+	// SomeDurations is a slice of time.Duration.
+	SomeDurations []time.Duration
+	// SomeKeyedString is a string field with a custom attribute key.
+	SomeKeyedString string `telemetry:"some_keyed_string"`
+	// SomeOmittedInt is an int64 field that is omitted when zero.
+	SomeOmittedInt int64 `telemetry:",omitempty"`
+	// SomeOmittedStrings is a slice of strings that is omitted when empty.
+	SomeOmittedStrings []string `telemetry:"some_omitted_strings,omitempty"`
+	SomeDocumentedBool bool     `telemetry:",doc=SomeDocumentedBool is a bool field, documented in the tag."`
+	SomeSkippedChannel chan int `telemetry:"-"`
 
 	subtests.AnotherData
 }
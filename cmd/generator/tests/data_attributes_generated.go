@@ -1,4 +1,5 @@
 //go:build generator
+
 package tests
 
 /*
@@ -6,8 +7,14 @@ This is a generated file. DO NOT EDIT.
 */
 
 import (
+	"errors"
+	"maps"
+	"slices"
+	"time"
+
 	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests"
 	"github.com/nginx/telemetry-exporter/pkg/telemetry"
 )
 
@@ -22,9 +29,905 @@ func (d *Data) Attributes() []attribute.KeyValue {
 	attrs = append(attrs, attribute.Int64Slice("SomeInts", d.SomeInts))
 	attrs = append(attrs, attribute.Float64Slice("SomeFloats", d.SomeFloats))
 	attrs = append(attrs, attribute.BoolSlice("SomeBools", d.SomeBools))
+	for _, k := range slices.Sorted(maps.Keys(d.SomeStringMap)) {
+		attrs = append(attrs, attribute.String("SomeStringMap."+k, d.SomeStringMap[k]))
+	}
+	for _, k := range slices.Sorted(maps.Keys(d.SomeIntMap)) {
+		attrs = append(attrs, attribute.Int64("SomeIntMap."+k, d.SomeIntMap[k]))
+	}
+	attrs = append(attrs, attribute.Int64("SomeNativeInt", int64(d.SomeNativeInt)))
+	attrs = append(attrs, attribute.Int64("SomeInt8", int64(d.SomeInt8)))
+	attrs = append(attrs, attribute.Int64("SomeInt16", int64(d.SomeInt16)))
+	attrs = append(attrs, attribute.Int64("SomeInt32", int64(d.SomeInt32)))
+	attrs = append(attrs, attribute.Int64("SomeUint8", int64(d.SomeUint8)))
+	attrs = append(attrs, attribute.Int64("SomeUint16", int64(d.SomeUint16)))
+	attrs = append(attrs, attribute.Int64("SomeUint32", int64(d.SomeUint32)))
+	attrs = append(attrs, attribute.Float64("SomeFloat32", float64(d.SomeFloat32)))
+	{
+		values := make([]int64, 0, len(d.SomeInt32s))
+		for _, v := range d.SomeInt32s {
+			values = append(values, int64(v))
+		}
+		attrs = append(attrs, attribute.Int64Slice("SomeInt32s", values))
+	}
+	{
+		values := make([]float64, 0, len(d.SomeFloat32s))
+		for _, v := range d.SomeFloat32s {
+			values = append(values, float64(v))
+		}
+		attrs = append(attrs, attribute.Float64Slice("SomeFloat32s", values))
+	}
+	for _, k := range slices.Sorted(maps.Keys(d.SomeUint32Map)) {
+		attrs = append(attrs, attribute.Int64("SomeUint32Map."+k, int64(d.SomeUint32Map[k])))
+	}
+	if d.SomePlatform != "" {
+		attrs = append(attrs, attribute.String("SomePlatform", string(d.SomePlatform)))
+	}
+	attrs = append(attrs, attribute.String("SomeLevel", d.SomeLevel.String()))
+	attrs = append(attrs, attribute.Int64("SomeCount", int64(d.SomeCount)))
+	{
+		values := make([]string, 0, len(d.SomePlatforms))
+		for _, v := range d.SomePlatforms {
+			values = append(values, string(v))
+		}
+		attrs = append(attrs, attribute.StringSlice("SomePlatforms", values))
+	}
+	for _, k := range slices.Sorted(maps.Keys(d.SomeLevelMap)) {
+		attrs = append(attrs, attribute.String("SomeLevelMap."+k, d.SomeLevelMap[k].String()))
+	}
+	if d.SomeStringPointer != nil {
+		attrs = append(attrs, attribute.String("SomeStringPointer", *d.SomeStringPointer))
+	}
+	if d.SomeIntPointer != nil {
+		attrs = append(attrs, attribute.Int64("SomeIntPointer", *d.SomeIntPointer))
+	}
+	if d.SomeFloatPointer != nil {
+		attrs = append(attrs, attribute.Float64("SomeFloatPointer", *d.SomeFloatPointer))
+	}
+	if d.SomeBoolPointer != nil {
+		attrs = append(attrs, attribute.Bool("SomeBoolPointer", *d.SomeBoolPointer))
+	}
+	if d.SomeCountPointer != nil {
+		attrs = append(attrs, attribute.Int64("SomeCountPointer", int64(*d.SomeCountPointer)))
+	}
+	if d.SomeLevelPointer != nil {
+		attrs = append(attrs, attribute.String("SomeLevelPointer", d.SomeLevelPointer.String()))
+	}
+	if !d.SomeTime.IsZero() {
+		attrs = append(attrs, attribute.Int64("SomeTime", d.SomeTime.UnixMilli()))
+	}
+	attrs = append(attrs, attribute.Int64("SomeDuration", d.SomeDuration.Milliseconds()))
+	if d.SomeTimePointer != nil && !d.SomeTimePointer.IsZero() {
+		attrs = append(attrs, attribute.Int64("SomeTimePointer", d.SomeTimePointer.UnixMilli()))
+	}
+	{
+		values := make([]int64, 0, len(d.SomeDurations))
+		for _, v := range d.SomeDurations {
+			values = append(values, v.Milliseconds())
+		}
+		attrs = append(attrs, attribute.Int64Slice("SomeDurations", values))
+	}
+	attrs = append(attrs, attribute.String("some_keyed_string", d.SomeKeyedString))
+	if d.SomeOmittedInt != 0 {
+		attrs = append(attrs, attribute.Int64("SomeOmittedInt", d.SomeOmittedInt))
+	}
+	if len(d.SomeOmittedStrings) > 0 {
+		attrs = append(attrs, attribute.StringSlice("some_omitted_strings", d.SomeOmittedStrings))
+	}
+	attrs = append(attrs, attribute.Bool("SomeDocumentedBool", d.SomeDocumentedBool))
 	attrs = append(attrs, d.AnotherData.Attributes()...)
 
 	return attrs
 }
 
+// DataFromAttributes creates Data from the attributes returned by its Attributes method.
+func DataFromAttributes(attrs []attribute.KeyValue) (Data, error) {
+	var d Data
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeString", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeString = v.AsString()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInt", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeInt = v.AsInt64()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeFloat", attribute.FLOAT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeFloat = v.AsFloat64()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeBool", attribute.BOOL)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeBool = v.AsBool()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeStrings", attribute.STRINGSLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeStrings = v.AsStringSlice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInts", attribute.INT64SLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeInts = v.AsInt64Slice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeFloats", attribute.FLOAT64SLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeFloats = v.AsFloat64Slice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeBools", attribute.BOOLSLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeBools = v.AsBoolSlice()
+	}
+
+	{
+		values, err := telemetry.LookupAttributeMap(attrs, "SomeStringMap", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		if values != nil {
+			d.SomeStringMap = make(map[string]string, len(values))
+			for k, v := range values {
+				d.SomeStringMap[k] = v.AsString()
+			}
+		}
+	}
+
+	{
+		values, err := telemetry.LookupAttributeMap(attrs, "SomeIntMap", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if values != nil {
+			d.SomeIntMap = make(map[string]int64, len(values))
+			for k, v := range values {
+				d.SomeIntMap[k] = v.AsInt64()
+			}
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeNativeInt", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeNativeInt, err = telemetry.ConvertAttributeInt[int]("SomeNativeInt", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInt8", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeInt8, err = telemetry.ConvertAttributeInt[int8]("SomeInt8", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInt16", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeInt16, err = telemetry.ConvertAttributeInt[int16]("SomeInt16", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInt32", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeInt32, err = telemetry.ConvertAttributeInt[int32]("SomeInt32", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeUint8", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeUint8, err = telemetry.ConvertAttributeInt[uint8]("SomeUint8", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeUint16", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeUint16, err = telemetry.ConvertAttributeInt[uint16]("SomeUint16", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeUint32", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeUint32, err = telemetry.ConvertAttributeInt[uint32]("SomeUint32", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeFloat32", attribute.FLOAT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeFloat32 = float32(v.AsFloat64())
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeInt32s", attribute.INT64SLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		values := v.AsInt64Slice()
+		d.SomeInt32s = make([]int32, 0, len(values))
+		for _, e := range values {
+			value, err := telemetry.ConvertAttributeInt[int32]("SomeInt32s", e)
+			if err != nil {
+				return Data{}, err
+			}
+			d.SomeInt32s = append(d.SomeInt32s, value)
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeFloat32s", attribute.FLOAT64SLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		values := v.AsFloat64Slice()
+		d.SomeFloat32s = make([]float32, 0, len(values))
+		for _, e := range values {
+			d.SomeFloat32s = append(d.SomeFloat32s, float32(e))
+		}
+	}
+
+	{
+		values, err := telemetry.LookupAttributeMap(attrs, "SomeUint32Map", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if values != nil {
+			d.SomeUint32Map = make(map[string]uint32, len(values))
+			for k, v := range values {
+				value, err := telemetry.ConvertAttributeInt[uint32](attribute.Key("SomeUint32Map."+k), v.AsInt64())
+				if err != nil {
+					return Data{}, err
+				}
+				d.SomeUint32Map[k] = value
+			}
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomePlatform", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			d.SomePlatform = Platform(v.AsString())
+		}
+	}
+
+	// SomeLevel is not set, because values of fmt.Stringer types can't be converted back.
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeCount", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeCount, err = telemetry.ConvertAttributeInt[Count]("SomeCount", v.AsInt64())
+		if err != nil {
+			return Data{}, err
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomePlatforms", attribute.STRINGSLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		values := v.AsStringSlice()
+		d.SomePlatforms = make([]Platform, 0, len(values))
+		for _, e := range values {
+			d.SomePlatforms = append(d.SomePlatforms, Platform(e))
+		}
+	}
+
+	// SomeLevelMap is not set, because values of fmt.Stringer types can't be converted back.
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeStringPointer", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value := v.AsString()
+			d.SomeStringPointer = &value
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeIntPointer", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value := v.AsInt64()
+			d.SomeIntPointer = &value
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeFloatPointer", attribute.FLOAT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value := v.AsFloat64()
+			d.SomeFloatPointer = &value
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeBoolPointer", attribute.BOOL)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value := v.AsBool()
+			d.SomeBoolPointer = &value
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeCountPointer", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value, err := telemetry.ConvertAttributeInt[Count]("SomeCountPointer", v.AsInt64())
+			if err != nil {
+				return Data{}, err
+			}
+			d.SomeCountPointer = &value
+		}
+	}
+
+	// SomeLevelPointer is not set, because values of fmt.Stringer types can't be converted back.
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeTime", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			d.SomeTime = time.UnixMilli(v.AsInt64())
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeDuration", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeDuration = time.Duration(v.AsInt64()) * time.Millisecond
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeTimePointer", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			value := time.UnixMilli(v.AsInt64())
+			d.SomeTimePointer = &value
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeDurations", attribute.INT64SLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		values := v.AsInt64Slice()
+		d.SomeDurations = make([]time.Duration, 0, len(values))
+		for _, e := range values {
+			d.SomeDurations = append(d.SomeDurations, time.Duration(e)*time.Millisecond)
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "some_keyed_string", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeKeyedString = v.AsString()
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "SomeOmittedInt", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			d.SomeOmittedInt = v.AsInt64()
+		}
+	}
+
+	{
+		v, exists, err := telemetry.LookupAttribute(attrs, "some_omitted_strings", attribute.STRINGSLICE)
+		if err != nil {
+			return Data{}, err
+		}
+		if exists {
+			d.SomeOmittedStrings = v.AsStringSlice()
+		}
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "SomeDocumentedBool", attribute.BOOL)
+		if err != nil {
+			return Data{}, err
+		}
+		d.SomeDocumentedBool = v.AsBool()
+	}
+
+	{
+		v, err := subtests.AnotherDataFromAttributes(attrs)
+		if err != nil {
+			return Data{}, err
+		}
+		d.AnotherData = v
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of Data,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *Data) TelemetryFields() []telemetry.FieldInfo {
+	return []telemetry.FieldInfo{
+		{
+			Key:         "SomeString",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "SomeString is a string field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInt",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "SomeInt is an int64 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeFloat",
+			GoType:      "float64",
+			AvroType:    "double",
+			Description: "SomeFloat is a float64 field.\nMore comments.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeBool",
+			GoType:      "bool",
+			AvroType:    "boolean",
+			Description: "SomeBool is a bool field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeStrings",
+			GoType:      "[]string",
+			AvroType:    "array<string>",
+			Description: "SomeStrings is a slice of strings.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInts",
+			GoType:      "[]int64",
+			AvroType:    "array<long>",
+			Description: "SomeInts is a slice of int64.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeFloats",
+			GoType:      "[]float64",
+			AvroType:    "array<double>",
+			Description: "SomeFloats is a slice of float64.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeBools",
+			GoType:      "[]bool",
+			AvroType:    "array<boolean>",
+			Description: "SomeBools is a slice of bool.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeStringMap",
+			GoType:      "map[string]string",
+			AvroType:    "map<string>",
+			Description: "SomeStringMap is a map of strings.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeIntMap",
+			GoType:      "map[string]int64",
+			AvroType:    "map<long>",
+			Description: "SomeIntMap is a map of int64.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeNativeInt",
+			GoType:      "int",
+			AvroType:    "long",
+			Description: "SomeNativeInt is an int field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInt8",
+			GoType:      "int8",
+			AvroType:    "long",
+			Description: "SomeInt8 is an int8 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInt16",
+			GoType:      "int16",
+			AvroType:    "long",
+			Description: "SomeInt16 is an int16 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInt32",
+			GoType:      "int32",
+			AvroType:    "long",
+			Description: "SomeInt32 is an int32 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeUint8",
+			GoType:      "uint8",
+			AvroType:    "long",
+			Description: "SomeUint8 is a uint8 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeUint16",
+			GoType:      "uint16",
+			AvroType:    "long",
+			Description: "SomeUint16 is a uint16 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeUint32",
+			GoType:      "uint32",
+			AvroType:    "long",
+			Description: "SomeUint32 is a uint32 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeFloat32",
+			GoType:      "float32",
+			AvroType:    "double",
+			Description: "SomeFloat32 is a float32 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeInt32s",
+			GoType:      "[]int32",
+			AvroType:    "array<long>",
+			Description: "SomeInt32s is a slice of int32.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeFloat32s",
+			GoType:      "[]float32",
+			AvroType:    "array<double>",
+			Description: "SomeFloat32s is a slice of float32.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeUint32Map",
+			GoType:      "map[string]uint32",
+			AvroType:    "map<long>",
+			Description: "SomeUint32Map is a map of uint32.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomePlatform",
+			GoType:      "tests.Platform",
+			AvroType:    "Platform",
+			Description: "SomePlatform is a named string field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeLevel",
+			GoType:      "tests.Level",
+			AvroType:    "string",
+			Description: "SomeLevel is a fmt.Stringer field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeCount",
+			GoType:      "tests.Count",
+			AvroType:    "long",
+			Description: "SomeCount is a named int32 field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomePlatforms",
+			GoType:      "[]tests.Platform",
+			AvroType:    "array<Platform>",
+			Description: "SomePlatforms is a slice of a named string type.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeLevelMap",
+			GoType:      "map[string]tests.Level",
+			AvroType:    "map<string>",
+			Description: "SomeLevelMap is a map of a fmt.Stringer type.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeStringPointer",
+			GoType:      "*string",
+			AvroType:    "string",
+			Description: "SomeStringPointer is a pointer to a string.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeIntPointer",
+			GoType:      "*int64",
+			AvroType:    "long",
+			Description: "SomeIntPointer is a pointer to an int64.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeFloatPointer",
+			GoType:      "*float64",
+			AvroType:    "double",
+			Description: "SomeFloatPointer is a pointer to a float64.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeBoolPointer",
+			GoType:      "*bool",
+			AvroType:    "boolean",
+			Description: "SomeBoolPointer is a pointer to a bool.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeCountPointer",
+			GoType:      "*tests.Count",
+			AvroType:    "long",
+			Description: "SomeCountPointer is a pointer to a named int32 type.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeLevelPointer",
+			GoType:      "*tests.Level",
+			AvroType:    "string",
+			Description: "SomeLevelPointer is a pointer to a fmt.Stringer type.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeTime",
+			GoType:      "time.Time",
+			AvroType:    "timestamp_ms",
+			Description: "SomeTime is a time.Time field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeDuration",
+			GoType:      "time.Duration",
+			AvroType:    "long",
+			Description: "SomeDuration is a time.Duration field.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeTimePointer",
+			GoType:      "*time.Time",
+			AvroType:    "timestamp_ms",
+			Description: "SomeTimePointer is a pointer to a time.Time.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeDurations",
+			GoType:      "[]time.Duration",
+			AvroType:    "array<long>",
+			Description: "SomeDurations is a slice of time.Duration.",
+			Source:      "Data",
+		},
+		{
+			Key:         "some_keyed_string",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "SomeKeyedString is a string field with a custom attribute key.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeOmittedInt",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "SomeOmittedInt is an int64 field that is omitted when zero.",
+			Source:      "Data",
+		},
+		{
+			Key:         "some_omitted_strings",
+			GoType:      "[]string",
+			AvroType:    "array<string>",
+			Description: "SomeOmittedStrings is a slice of strings that is omitted when empty.",
+			Source:      "Data",
+		},
+		{
+			Key:         "SomeDocumentedBool",
+			GoType:      "bool",
+			AvroType:    "boolean",
+			Description: "SomeDocumentedBool is a bool field, documented in the tag.",
+			Source:      "Data",
+		},
+		{
+			Key:         "AnotherSomeString",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "AnotherSomeString is a string field.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeInt",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "AnotherSomeInt is an int64 field.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeFloat",
+			GoType:      "float64",
+			AvroType:    "double",
+			Description: "AnotherSomeFloat is a float64 field.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeBool",
+			GoType:      "bool",
+			AvroType:    "boolean",
+			Description: "AnotherSomeBool is a bool field.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeStrings",
+			GoType:      "[]string",
+			AvroType:    "array<string>",
+			Description: "AnotherSomeStrings is a slice of strings.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeInts",
+			GoType:      "[]int64",
+			AvroType:    "array<long>",
+			Description: "AnotherSomeInts is a slice of int64.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeFloats",
+			GoType:      "[]float64",
+			AvroType:    "array<double>",
+			Description: "AnotherSomeFloats is a slice of float64.",
+			Source:      "subtests.AnotherData",
+		},
+		{
+			Key:         "AnotherSomeBools",
+			GoType:      "[]bool",
+			AvroType:    "array<boolean>",
+			Description: "AnotherSomeBools is a slice of bool.",
+			Source:      "subtests.AnotherData",
+		},
+	}
+}
+
+var _ telemetry.Describable = (*Data)(nil)
+
+// Validate returns an error if the fields of Data violate their validation rules.
+func (d *Data) Validate() error {
+	var errs []error
+
+	if d.SomeString == "" {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeString",
+			Message: "is required",
+		})
+	}
+
+	if len(d.SomeStrings) > 5 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeStrings",
+			Message: "must have at most 5 items",
+		})
+	}
+
+	if len(d.SomeIntMap) > 10 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeIntMap",
+			Message: "must have at most 10 items",
+		})
+	}
+
+	if d.SomeInt8 < -10 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeInt8",
+			Message: "must be at least -10",
+		})
+	}
+
+	if d.SomeInt8 > 10 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeInt8",
+			Message: "must be at most 10",
+		})
+	}
+
+	if d.SomeCountPointer != nil && *d.SomeCountPointer < 1 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "SomeCountPointer",
+			Message: "must be at least 1",
+		})
+	}
+
+	if err := d.AnotherData.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+var _ telemetry.Validatable = (*Data)(nil)
+
 var _ telemetry.Exportable = (*Data)(nil)
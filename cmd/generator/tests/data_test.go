@@ -3,14 +3,23 @@
 package tests
 
 import (
+	"errors"
+	"math"
+	"strings"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/nginx/telemetry-exporter/cmd/generator/tests/subtests"
+	"github.com/nginx/telemetry-exporter/pkg/telemetry"
 )
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func TestData_Attributes(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
@@ -24,6 +33,49 @@ func TestData_Attributes(t *testing.T) {
 		SomeInts:    []int64{1, 2, 3},
 		SomeFloats:  []float64{1.1, 2.2, 3.3},
 		SomeBools:   []bool{true, false, true},
+		SomeStringMap: map[string]string{
+			"b": "second",
+			"a": "first",
+		},
+		SomeIntMap: map[string]int64{
+			"y": 2,
+			"x": 1,
+		},
+		SomeNativeInt: 1,
+		SomeInt8:      -8,
+		SomeInt16:     -16,
+		SomeInt32:     -32,
+		SomeUint8:     8,
+		SomeUint16:    16,
+		SomeUint32:    math.MaxUint32,
+		SomeFloat32:   0.5,
+		SomeInt32s:    []int32{math.MinInt32, math.MaxInt32},
+		SomeFloat32s:  []float32{0.25, 0.75},
+		SomeUint32Map: map[string]uint32{
+			"max": math.MaxUint32,
+		},
+		SomePlatform:  PlatformAWS,
+		SomeLevel:     LevelHigh,
+		SomeCount:     7,
+		SomePlatforms: []Platform{PlatformGCP, PlatformOther},
+		SomeLevelMap: map[string]Level{
+			"a": LevelLow,
+		},
+		SomeStringPointer:  ptr("pointer"),
+		SomeIntPointer:     ptr[int64](0),
+		SomeFloatPointer:   ptr(2.5),
+		SomeBoolPointer:    ptr(false),
+		SomeCountPointer:   ptr[Count](3),
+		SomeLevelPointer:   ptr(LevelHigh),
+		SomeTime:           time.UnixMilli(1700000000123),
+		SomeDuration:       1500 * time.Millisecond,
+		SomeTimePointer:    ptr(time.UnixMilli(1600000000456)),
+		SomeDurations:      []time.Duration{time.Second, time.Minute},
+		SomeKeyedString:    "keyed",
+		SomeOmittedInt:     5,
+		SomeOmittedStrings: []string{"g"},
+		SomeDocumentedBool: true,
+		SomeSkippedChannel: make(chan int),
 		AnotherData: subtests.AnotherData{
 			AnotherSomeString:  "another string",
 			AnotherSomeInt:     24,
@@ -46,6 +98,40 @@ func TestData_Attributes(t *testing.T) {
 		attribute.Int64Slice("SomeInts", []int64{1, 2, 3}),
 		attribute.Float64Slice("SomeFloats", []float64{1.1, 2.2, 3.3}),
 		attribute.BoolSlice("SomeBools", []bool{true, false, true}),
+		attribute.String("SomeStringMap.a", "first"),
+		attribute.String("SomeStringMap.b", "second"),
+		attribute.Int64("SomeIntMap.x", 1),
+		attribute.Int64("SomeIntMap.y", 2),
+		attribute.Int64("SomeNativeInt", 1),
+		attribute.Int64("SomeInt8", -8),
+		attribute.Int64("SomeInt16", -16),
+		attribute.Int64("SomeInt32", -32),
+		attribute.Int64("SomeUint8", 8),
+		attribute.Int64("SomeUint16", 16),
+		attribute.Int64("SomeUint32", math.MaxUint32),
+		attribute.Float64("SomeFloat32", 0.5),
+		attribute.Int64Slice("SomeInt32s", []int64{math.MinInt32, math.MaxInt32}),
+		attribute.Float64Slice("SomeFloat32s", []float64{0.25, 0.75}),
+		attribute.Int64("SomeUint32Map.max", math.MaxUint32),
+		attribute.String("SomePlatform", "aws"),
+		attribute.String("SomeLevel", "high"),
+		attribute.Int64("SomeCount", 7),
+		attribute.StringSlice("SomePlatforms", []string{"gcp", "other"}),
+		attribute.String("SomeLevelMap.a", "low"),
+		attribute.String("SomeStringPointer", "pointer"),
+		attribute.Int64("SomeIntPointer", 0),
+		attribute.Float64("SomeFloatPointer", 2.5),
+		attribute.Bool("SomeBoolPointer", false),
+		attribute.Int64("SomeCountPointer", 3),
+		attribute.String("SomeLevelPointer", "high"),
+		attribute.Int64("SomeTime", 1700000000123),
+		attribute.Int64("SomeDuration", 1500),
+		attribute.Int64("SomeTimePointer", 1600000000456),
+		attribute.Int64Slice("SomeDurations", []int64{1000, 60000}),
+		attribute.String("some_keyed_string", "keyed"),
+		attribute.Int64("SomeOmittedInt", 5),
+		attribute.StringSlice("some_omitted_strings", []string{"g"}),
+		attribute.Bool("SomeDocumentedBool", true),
 		attribute.String("AnotherSomeString", "another string"),
 		attribute.Int64("AnotherSomeInt", 24),
 		attribute.Float64("AnotherSomeFloat", 1.41),
@@ -61,12 +147,41 @@ func TestData_Attributes(t *testing.T) {
 	g.Expect(attributes).To(ConsistOf(expectedAttributes))
 }
 
+func TestData_AttributesMapOrder(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	data := Data{
+		SomeIntMap: map[string]int64{
+			"c": 3,
+			"a": 1,
+			"b": 2,
+		},
+	}
+
+	var mapAttributes []attribute.KeyValue
+	for _, attr := range data.Attributes() {
+		if strings.HasPrefix(string(attr.Key), "SomeIntMap.") {
+			mapAttributes = append(mapAttributes, attr)
+		}
+	}
+
+	expectedAttributes := []attribute.KeyValue{
+		attribute.Int64("SomeIntMap.a", 1),
+		attribute.Int64("SomeIntMap.b", 2),
+		attribute.Int64("SomeIntMap.c", 3),
+	}
+
+	g.Expect(mapAttributes).To(Equal(expectedAttributes))
+}
+
 func TestData_AttributesEmpty(t *testing.T) {
 	t.Parallel()
 	g := NewGomegaWithT(t)
 
 	data := Data{}
 
+	// the zero values of enums and timestamps are not valid in the schemes, so they are omitted
 	expectedAttributes := []attribute.KeyValue{
 		attribute.String("dataType", "ngf-product-telemetry"),
 		attribute.String("SomeString", ""),
@@ -77,6 +192,23 @@ func TestData_AttributesEmpty(t *testing.T) {
 		attribute.Int64Slice("SomeInts", []int64{}),
 		attribute.Float64Slice("SomeFloats", []float64{}),
 		attribute.BoolSlice("SomeBools", []bool{}),
+		attribute.Int64("SomeNativeInt", 0),
+		attribute.Int64("SomeInt8", 0),
+		attribute.Int64("SomeInt16", 0),
+		attribute.Int64("SomeInt32", 0),
+		attribute.Int64("SomeUint8", 0),
+		attribute.Int64("SomeUint16", 0),
+		attribute.Int64("SomeUint32", 0),
+		attribute.Float64("SomeFloat32", 0),
+		attribute.Int64Slice("SomeInt32s", []int64{}),
+		attribute.Float64Slice("SomeFloat32s", []float64{}),
+		attribute.String("SomeLevel", "low"),
+		attribute.Int64("SomeCount", 0),
+		attribute.StringSlice("SomePlatforms", []string{}),
+		attribute.Int64("SomeDuration", 0),
+		attribute.Int64Slice("SomeDurations", []int64{}),
+		attribute.String("some_keyed_string", ""),
+		attribute.Bool("SomeDocumentedBool", false),
 		attribute.String("AnotherSomeString", ""),
 		attribute.Int64("AnotherSomeInt", 0),
 		attribute.Float64("AnotherSomeFloat", 0),
@@ -91,3 +223,340 @@ func TestData_AttributesEmpty(t *testing.T) {
 
 	g.Expect(attributes).To(ConsistOf(expectedAttributes))
 }
+
+func TestDataFromAttributes(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	// fmt.Stringer values can't be converted back, so they're left unset
+	data := Data{
+		SomeString:    "some string",
+		SomeInt:       42,
+		SomeFloat:     3.14,
+		SomeBool:      true,
+		SomeStrings:   []string{"a", "b", "c"},
+		SomeInts:      []int64{1, 2, 3},
+		SomeFloats:    []float64{1.1, 2.2, 3.3},
+		SomeBools:     []bool{true, false, true},
+		SomeStringMap: map[string]string{"a": "first", "b": "second"},
+		SomeIntMap:    map[string]int64{"x": 1},
+		SomeNativeInt: 1,
+		SomeInt8:      -8,
+		SomeInt16:     -16,
+		SomeInt32:     -32,
+		SomeUint8:     8,
+		SomeUint16:    16,
+		SomeUint32:    math.MaxUint32,
+		SomeFloat32:   0.5,
+		SomeInt32s:    []int32{math.MinInt32, math.MaxInt32},
+		SomeFloat32s:  []float32{0.25, 0.75},
+		SomeUint32Map: map[string]uint32{
+			"max": math.MaxUint32,
+		},
+		SomePlatform:       PlatformAWS,
+		SomeCount:          7,
+		SomePlatforms:      []Platform{PlatformGCP, PlatformOther},
+		SomeStringPointer:  ptr("pointer"),
+		SomeIntPointer:     ptr[int64](0),
+		SomeFloatPointer:   ptr(2.5),
+		SomeBoolPointer:    ptr(false),
+		SomeCountPointer:   ptr[Count](3),
+		SomeTime:           time.UnixMilli(1700000000123),
+		SomeDuration:       1500 * time.Millisecond,
+		SomeTimePointer:    ptr(time.UnixMilli(1600000000456)),
+		SomeDurations:      []time.Duration{time.Second, time.Minute},
+		SomeKeyedString:    "keyed",
+		SomeOmittedInt:     5,
+		SomeOmittedStrings: []string{"g"},
+		SomeDocumentedBool: true,
+		AnotherData: subtests.AnotherData{
+			AnotherSomeString:  "another string",
+			AnotherSomeInt:     24,
+			AnotherSomeFloat:   1.41,
+			AnotherSomeStrings: []string{"d", "e", "f"},
+			AnotherSomeInts:    []int64{4, 5, 6},
+			AnotherSomeFloats:  []float64{4.4, 5.5, 6.6},
+			AnotherSomeBools:   []bool{false, true, false},
+		},
+	}
+
+	result, err := DataFromAttributes(data.Attributes())
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result).To(Equal(data))
+}
+
+func TestDataFromAttributesEmpty(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	data := Data{}
+
+	result, err := DataFromAttributes(data.Attributes())
+
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(result.SomeStringPointer).To(BeNil())
+	g.Expect(result.SomeTime.IsZero()).To(BeTrue())
+	g.Expect(result.SomeStringMap).To(BeNil())
+	g.Expect(result.SomeOmittedStrings).To(BeNil())
+	g.Expect(result.SomeStrings).To(BeEmpty())
+}
+
+func TestDataFromAttributesErrors(t *testing.T) {
+	t.Parallel()
+
+	var data Data
+
+	withAttribute := func(attr attribute.KeyValue) []attribute.KeyValue {
+		attrs := data.Attributes()
+
+		for i := range attrs {
+			if attrs[i].Key == attr.Key {
+				attrs[i] = attr
+				return attrs
+			}
+		}
+
+		return append(attrs, attr)
+	}
+
+	withoutAttribute := func(key attribute.Key) []attribute.KeyValue {
+		var attrs []attribute.KeyValue
+
+		for _, attr := range data.Attributes() {
+			if attr.Key != key {
+				attrs = append(attrs, attr)
+			}
+		}
+
+		return attrs
+	}
+
+	tests := []struct {
+		expectedErr error
+		name        string
+		attrs       []attribute.KeyValue
+	}{
+		{
+			name:        "missing attribute",
+			attrs:       withoutAttribute("SomeInt"),
+			expectedErr: &telemetry.MissingAttributeError{Key: "SomeInt"},
+		},
+		{
+			name:        "missing attribute of embedded struct",
+			attrs:       withoutAttribute("AnotherSomeInt"),
+			expectedErr: &telemetry.MissingAttributeError{Key: "AnotherSomeInt"},
+		},
+		{
+			name:  "mistyped attribute",
+			attrs: withAttribute(attribute.String("SomeInt", "42")),
+			expectedErr: &telemetry.AttributeTypeError{
+				Key:      "SomeInt",
+				Expected: attribute.INT64,
+				Actual:   attribute.STRING,
+			},
+		},
+		{
+			name:  "mistyped optional attribute",
+			attrs: withAttribute(attribute.Int64("SomeStringPointer", 1)),
+			expectedErr: &telemetry.AttributeTypeError{
+				Key:      "SomeStringPointer",
+				Expected: attribute.STRING,
+				Actual:   attribute.INT64,
+			},
+		},
+		{
+			name:  "mistyped map value",
+			attrs: withAttribute(attribute.Bool("SomeIntMap.x", true)),
+			expectedErr: &telemetry.AttributeTypeError{
+				Key:      "SomeIntMap.x",
+				Expected: attribute.INT64,
+				Actual:   attribute.BOOL,
+			},
+		},
+		{
+			name:        "out of range attribute",
+			attrs:       withAttribute(attribute.Int64("SomeInt8", 300)),
+			expectedErr: &telemetry.AttributeRangeError{Key: "SomeInt8", Type: "int8", Value: 300},
+		},
+		{
+			name:        "negative unsigned attribute",
+			attrs:       withAttribute(attribute.Int64("SomeUint32", -1)),
+			expectedErr: &telemetry.AttributeRangeError{Key: "SomeUint32", Type: "uint32", Value: -1},
+		},
+		{
+			name:        "out of range slice value",
+			attrs:       withAttribute(attribute.Int64Slice("SomeInt32s", []int64{1, 1 << 40})),
+			expectedErr: &telemetry.AttributeRangeError{Key: "SomeInt32s", Type: "int32", Value: 1 << 40},
+		},
+		{
+			name:        "out of range map value",
+			attrs:       withAttribute(attribute.Int64("SomeUint32Map.x", 1<<32)),
+			expectedErr: &telemetry.AttributeRangeError{Key: "SomeUint32Map.x", Type: "uint32", Value: 1 << 32},
+		},
+		{
+			name:        "out of range value of named type",
+			attrs:       withAttribute(attribute.Int64("SomeCountPointer", 1<<40)),
+			expectedErr: &telemetry.AttributeRangeError{Key: "SomeCountPointer", Type: "tests.Count", Value: 1 << 40},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			_, err := DataFromAttributes(test.attrs)
+
+			g.Expect(err).To(Equal(test.expectedErr))
+		})
+	}
+}
+
+func TestData_Validate(t *testing.T) {
+	t.Parallel()
+
+	validData := func() Data {
+		return Data{
+			SomeString:       "some string",
+			SomeStrings:      []string{"a", "b"},
+			SomeInt8:         -8,
+			SomeCountPointer: ptr[Count](1),
+		}
+	}
+
+	tests := []struct {
+		modify         func(d *Data)
+		name           string
+		expectedErrMsg string
+	}{
+		{
+			name:   "valid",
+			modify: func(_ *Data) {},
+		},
+		{
+			name:           "missing required field",
+			modify:         func(d *Data) { d.SomeString = "" },
+			expectedErrMsg: "field SomeString is required",
+		},
+		{
+			name:           "too many items",
+			modify:         func(d *Data) { d.SomeStrings = []string{"a", "b", "c", "d", "e", "f"} },
+			expectedErrMsg: "field SomeStrings must have at most 5 items",
+		},
+		{
+			name:           "below minimum",
+			modify:         func(d *Data) { d.SomeInt8 = -11 },
+			expectedErrMsg: "field SomeInt8 must be at least -10",
+		},
+		{
+			name:           "above maximum",
+			modify:         func(d *Data) { d.SomeInt8 = 11 },
+			expectedErrMsg: "field SomeInt8 must be at most 10",
+		},
+		{
+			name:   "unset pointer",
+			modify: func(d *Data) { d.SomeCountPointer = nil },
+		},
+		{
+			name:           "pointer below minimum",
+			modify:         func(d *Data) { d.SomeCountPointer = ptr[Count](0) },
+			expectedErrMsg: "field SomeCountPointer must be at least 1",
+		},
+		{
+			name:           "invalid embedded struct",
+			modify:         func(d *Data) { d.AnotherSomeInt = -1 },
+			expectedErrMsg: "field AnotherSomeInt must be at least 0",
+		},
+		{
+			name: "multiple invalid fields",
+			modify: func(d *Data) {
+				d.SomeString = ""
+				d.AnotherSomeInt = -1
+			},
+			expectedErrMsg: "field SomeString is required\nfield AnotherSomeInt must be at least 0",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			data := validData()
+			test.modify(&data)
+
+			err := data.Validate()
+
+			if test.expectedErrMsg == "" {
+				g.Expect(err).ToNot(HaveOccurred())
+				return
+			}
+
+			g.Expect(err).To(MatchError(test.expectedErrMsg))
+
+			var validationErr *telemetry.ValidationError
+			g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+		})
+	}
+}
+
+func TestData_TelemetryFields(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	var data *Data
+
+	fields := data.TelemetryFields()
+
+	keys := make([]string, 0, len(fields))
+	for _, f := range fields {
+		keys = append(keys, f.Key)
+	}
+
+	// every attribute of the data is described
+	var attributeData Data
+	for _, attr := range attributeData.Attributes() {
+		if attr.Key != "dataType" {
+			g.Expect(keys).To(ContainElement(string(attr.Key)))
+		}
+	}
+
+	g.Expect(fields).To(ContainElements(
+		telemetry.FieldInfo{
+			Key:         "SomeFloat",
+			GoType:      "float64",
+			AvroType:    "double",
+			Description: "SomeFloat is a float64 field.\nMore comments.",
+			Source:      "Data",
+		},
+		telemetry.FieldInfo{
+			Key:         "SomePlatforms",
+			GoType:      "[]tests.Platform",
+			AvroType:    "array<Platform>",
+			Description: "SomePlatforms is a slice of a named string type.",
+			Source:      "Data",
+		},
+		telemetry.FieldInfo{
+			Key:         "SomeTimePointer",
+			GoType:      "*time.Time",
+			AvroType:    "timestamp_ms",
+			Description: "SomeTimePointer is a pointer to a time.Time.",
+			Source:      "Data",
+		},
+		telemetry.FieldInfo{
+			Key:         "SomeIntMap",
+			GoType:      "map[string]int64",
+			AvroType:    "map<long>",
+			Description: "SomeIntMap is a map of int64.",
+			Source:      "Data",
+		},
+		telemetry.FieldInfo{
+			Key:         "AnotherSomeInt",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "AnotherSomeInt is an int64 field.",
+			Source:      "subtests.AnotherData",
+		},
+	))
+}
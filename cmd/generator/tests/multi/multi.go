@@ -0,0 +1,38 @@
+//go:build generator
+
+// Package multi is used to ensure that the generator produces the correct code for multiple structs of a package
+// in a single file.
+// Correctness is confirmed by the fact the generated code compiles.
+//
+//go:generate go run -tags generator github.com/nginx/telemetry-exporter/cmd/generator -all
+package multi
+
+// ClusterData is discovered by the generator because of its marker comment.
+//
+// +telemetry:generate
+// +telemetry:build-tags=generator
+type ClusterData struct {
+	// ClusterID is the ID of the cluster.
+	ClusterID string
+	// NodeCount is the number of nodes in the cluster.
+	NodeCount int64
+}
+
+// ControllerData is discovered by the generator because of its marker comment.
+// Its scheme is generated because of its scheme marker comments.
+//
+// +telemetry:generate
+// +telemetry:scheme:protocol=ControllerTelemetry
+// +telemetry:scheme:namespace=controller.nginx.org
+// +telemetry:scheme:datatype=controller-telemetry
+type ControllerData struct {
+	// Version is the version of the controller.
+	Version string
+	ClusterData
+}
+
+// IgnoredData is not discovered by the generator because it doesn't have the marker comment.
+type IgnoredData struct {
+	// Ignored is an ignored field.
+	Ignored chan int
+}
@@ -0,0 +1,135 @@
+//go:build generator
+
+package multi
+
+/*
+This is a generated file. DO NOT EDIT.
+*/
+
+import (
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/nginx/telemetry-exporter/pkg/telemetry"
+)
+
+func (d *ClusterData) Attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	attrs = append(attrs, attribute.String("ClusterID", d.ClusterID))
+	attrs = append(attrs, attribute.Int64("NodeCount", d.NodeCount))
+
+	return attrs
+}
+
+// ClusterDataFromAttributes creates ClusterData from the attributes returned by its Attributes method.
+func ClusterDataFromAttributes(attrs []attribute.KeyValue) (ClusterData, error) {
+	var d ClusterData
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "ClusterID", attribute.STRING)
+		if err != nil {
+			return ClusterData{}, err
+		}
+		d.ClusterID = v.AsString()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "NodeCount", attribute.INT64)
+		if err != nil {
+			return ClusterData{}, err
+		}
+		d.NodeCount = v.AsInt64()
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of ClusterData,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *ClusterData) TelemetryFields() []telemetry.FieldInfo {
+	return []telemetry.FieldInfo{
+		{
+			Key:         "ClusterID",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ClusterID is the ID of the cluster.",
+			Source:      "ClusterData",
+		},
+		{
+			Key:         "NodeCount",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "NodeCount is the number of nodes in the cluster.",
+			Source:      "ClusterData",
+		},
+	}
+}
+
+var _ telemetry.Describable = (*ClusterData)(nil)
+
+var _ telemetry.Exportable = (*ClusterData)(nil)
+
+func (d *ControllerData) Attributes() []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	attrs = append(attrs, attribute.String("dataType", "controller-telemetry"))
+	attrs = append(attrs, attribute.String("Version", d.Version))
+	attrs = append(attrs, d.ClusterData.Attributes()...)
+
+	return attrs
+}
+
+// ControllerDataFromAttributes creates ControllerData from the attributes returned by its Attributes method.
+func ControllerDataFromAttributes(attrs []attribute.KeyValue) (ControllerData, error) {
+	var d ControllerData
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "Version", attribute.STRING)
+		if err != nil {
+			return ControllerData{}, err
+		}
+		d.Version = v.AsString()
+	}
+
+	{
+		v, err := ClusterDataFromAttributes(attrs)
+		if err != nil {
+			return ControllerData{}, err
+		}
+		d.ClusterData = v
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of ControllerData,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *ControllerData) TelemetryFields() []telemetry.FieldInfo {
+	return []telemetry.FieldInfo{
+		{
+			Key:         "Version",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "Version is the version of the controller.",
+			Source:      "ControllerData",
+		},
+		{
+			Key:         "ClusterID",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ClusterID is the ID of the cluster.",
+			Source:      "multi.ClusterData",
+		},
+		{
+			Key:         "NodeCount",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "NodeCount is the number of nodes in the cluster.",
+			Source:      "multi.ClusterData",
+		},
+	}
+}
+
+var _ telemetry.Describable = (*ControllerData)(nil)
+
+var _ telemetry.Exportable = (*ControllerData)(nil)
@@ -12,6 +12,7 @@ type AnotherData struct {
 	// AnotherSomeString is a string field.
 	AnotherSomeString string
 	// AnotherSomeInt is an int64 field.
+	// +telemetry:min=0
 	AnotherSomeInt int64
 	// AnotherSomeFloat is a float64 field.
 	AnotherSomeFloat float64
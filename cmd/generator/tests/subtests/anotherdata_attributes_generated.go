@@ -1,4 +1,5 @@
 //go:build generator
+
 package subtests
 
 /*
@@ -6,6 +7,8 @@ This is a generated file. DO NOT EDIT.
 */
 
 import (
+	"errors"
+
 	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/nginx/telemetry-exporter/pkg/telemetry"
@@ -25,4 +28,157 @@ func (d *AnotherData) Attributes() []attribute.KeyValue {
 	return attrs
 }
 
+// AnotherDataFromAttributes creates AnotherData from the attributes returned by its Attributes method.
+func AnotherDataFromAttributes(attrs []attribute.KeyValue) (AnotherData, error) {
+	var d AnotherData
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeString", attribute.STRING)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeString = v.AsString()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeInt", attribute.INT64)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeInt = v.AsInt64()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeFloat", attribute.FLOAT64)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeFloat = v.AsFloat64()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeBool", attribute.BOOL)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeBool = v.AsBool()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeStrings", attribute.STRINGSLICE)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeStrings = v.AsStringSlice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeInts", attribute.INT64SLICE)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeInts = v.AsInt64Slice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeFloats", attribute.FLOAT64SLICE)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeFloats = v.AsFloat64Slice()
+	}
+
+	{
+		v, err := telemetry.RequireAttribute(attrs, "AnotherSomeBools", attribute.BOOLSLICE)
+		if err != nil {
+			return AnotherData{}, err
+		}
+		d.AnotherSomeBools = v.AsBoolSlice()
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of AnotherData,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *AnotherData) TelemetryFields() []telemetry.FieldInfo {
+	return []telemetry.FieldInfo{
+		{
+			Key:         "AnotherSomeString",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "AnotherSomeString is a string field.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeInt",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "AnotherSomeInt is an int64 field.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeFloat",
+			GoType:      "float64",
+			AvroType:    "double",
+			Description: "AnotherSomeFloat is a float64 field.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeBool",
+			GoType:      "bool",
+			AvroType:    "boolean",
+			Description: "AnotherSomeBool is a bool field.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeStrings",
+			GoType:      "[]string",
+			AvroType:    "array<string>",
+			Description: "AnotherSomeStrings is a slice of strings.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeInts",
+			GoType:      "[]int64",
+			AvroType:    "array<long>",
+			Description: "AnotherSomeInts is a slice of int64.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeFloats",
+			GoType:      "[]float64",
+			AvroType:    "array<double>",
+			Description: "AnotherSomeFloats is a slice of float64.",
+			Source:      "AnotherData",
+		},
+		{
+			Key:         "AnotherSomeBools",
+			GoType:      "[]bool",
+			AvroType:    "array<boolean>",
+			Description: "AnotherSomeBools is a slice of bool.",
+			Source:      "AnotherData",
+		},
+	}
+}
+
+var _ telemetry.Describable = (*AnotherData)(nil)
+
+// Validate returns an error if the fields of AnotherData violate their validation rules.
+func (d *AnotherData) Validate() error {
+	var errs []error
+
+	if d.AnotherSomeInt < 0 {
+		errs = append(errs, &telemetry.ValidationError{
+			Field:   "AnotherSomeInt",
+			Message: "must be at least 0",
+		})
+	}
+
+	return errors.Join(errs...)
+}
+
+var _ telemetry.Validatable = (*AnotherData)(nil)
+
 var _ telemetry.Exportable = (*AnotherData)(nil)
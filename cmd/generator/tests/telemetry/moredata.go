@@ -5,8 +5,9 @@ package telemetry
 // MoreData is used to ensure that the generator produces the correct code for a struct in a package with the name
 // 'telemetry'.
 // Correctness is confirmed by the fact the generated code compiles.
+// The settings of the generation are set by the config file.
 //
-//go:generate go run -tags generator github.com/nginx/telemetry-exporter/cmd/generator -type=MoreData -build-tags=generator
+//go:generate go run -tags generator github.com/nginx/telemetry-exporter/cmd/generator -config=../config.yaml
 type MoreData struct {
 	// StringField is a string field.
 	StringField string
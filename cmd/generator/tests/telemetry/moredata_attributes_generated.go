@@ -1,4 +1,5 @@
 //go:build generator
+
 package telemetry
 
 /*
@@ -18,4 +19,36 @@ func (d *MoreData) Attributes() []attribute.KeyValue {
 	return attrs
 }
 
+// MoreDataFromAttributes creates MoreData from the attributes returned by its Attributes method.
+func MoreDataFromAttributes(attrs []attribute.KeyValue) (MoreData, error) {
+	var d MoreData
+
+	{
+		v, err := ngxTelemetry.RequireAttribute(attrs, "StringField", attribute.STRING)
+		if err != nil {
+			return MoreData{}, err
+		}
+		d.StringField = v.AsString()
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of MoreData,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *MoreData) TelemetryFields() []ngxTelemetry.FieldInfo {
+	return []ngxTelemetry.FieldInfo{
+		{
+			Key:         "StringField",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "StringField is a string field.",
+			Source:      "MoreData",
+		},
+	}
+}
+
+var _ ngxTelemetry.Describable = (*MoreData)(nil)
+
 var _ ngxTelemetry.Exportable = (*MoreData)(nil)
@@ -0,0 +1,40 @@
+//go:build generator
+
+package tests
+
+// Platform is a named string type. Its typed constants make it an enum in the scheme.
+type Platform string
+
+const (
+	// PlatformAWS is the AWS platform.
+	PlatformAWS Platform = "aws"
+	// PlatformGCP is the GCP platform.
+	PlatformGCP Platform = "gcp"
+	// PlatformOther is any other platform.
+	PlatformOther Platform = "other"
+)
+
+// Level is an int-based enum that implements fmt.Stringer, so it is exported as a string.
+type Level int
+
+const (
+	// LevelLow is the low level.
+	LevelLow Level = iota
+	// LevelHigh is the high level.
+	LevelHigh
+)
+
+// String implements fmt.Stringer.
+func (l Level) String() string {
+	switch l {
+	case LevelLow:
+		return "low"
+	case LevelHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+// Count is a named int32 type.
+type Count int32
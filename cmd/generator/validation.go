@@ -0,0 +1,295 @@
+//go:build generator
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"go/types"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// validationRequired requires the field to be set: not the zero value, nil or empty.
+	validationRequired = "required"
+	// validationMin sets the minimum value of a number field.
+	validationMin = "min"
+	// validationMax sets the maximum value of a number field.
+	validationMax = "max"
+	// validationMaxItems sets the maximum number of items of a slice or map field.
+	validationMaxItems = "maxItems"
+)
+
+// fieldValidation are the validation rules of a field, set by the options of its telemetry tag or by the marker
+// comments of the field, like +telemetry:min=0. Empty values mean the rules are not set.
+type fieldValidation struct {
+	// min is the minimum value of a number field.
+	min string
+	// max is the maximum value of a number field.
+	max string
+	// maxItems is the maximum number of items of a slice or map field.
+	maxItems string
+	// required means the field must be set.
+	required bool
+}
+
+// isSet returns true if any rule is set.
+func (v fieldValidation) isSet() bool {
+	return v != fieldValidation{}
+}
+
+// applyValidationRule sets the validation rule with the name to the value.
+// It returns false if there is no such rule.
+func (v *fieldValidation) applyValidationRule(name, value string, hasValue bool) (bool, error) {
+	var target *string
+
+	switch name {
+	case validationRequired:
+		if hasValue {
+			return true, fmt.Errorf("validation rule %s doesn't accept a value", name)
+		}
+
+		v.required = true
+
+		return true, nil
+	case validationMin:
+		target = &v.min
+	case validationMax:
+		target = &v.max
+	case validationMaxItems:
+		target = &v.maxItems
+	default:
+		return false, nil
+	}
+
+	if value == "" {
+		return true, fmt.Errorf("validation rule %s requires a value", name)
+	}
+
+	if name == validationMaxItems {
+		if n, err := strconv.Atoi(value); err != nil || n < 0 {
+			return true, fmt.Errorf("validation rule %s must be a non-negative integer, got %s", name, value)
+		}
+	} else if n, err := strconv.ParseFloat(value, 64); err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return true, fmt.Errorf("validation rule %s must be a finite number, got %s", name, value)
+	}
+
+	*target = value
+
+	return true, nil
+}
+
+// splitFieldMarkers splits the comment of a field into its doc string and its marker comments, without the
+// marker prefix.
+func splitFieldMarkers(comment string) (doc string, markers []string) {
+	var docLines []string
+
+	for _, line := range strings.Split(comment, "\n") {
+		if marker, ok := strings.CutPrefix(strings.TrimSpace(line), markerPrefix); ok {
+			markers = append(markers, marker)
+			continue
+		}
+
+		docLines = append(docLines, line)
+	}
+
+	return strings.Join(docLines, "\n"), markers
+}
+
+// parseFieldMarkers applies the validation rules of the marker comments of a field.
+func (v *fieldValidation) parseFieldMarkers(markers []string) error {
+	for _, marker := range markers {
+		name, value, hasValue := strings.Cut(marker, "=")
+
+		found, err := v.applyValidationRule(name, value, hasValue)
+		if err != nil {
+			return fmt.Errorf("marker %s%s: %w", markerPrefix, name, err)
+		}
+
+		if !found {
+			return fmt.Errorf("unknown marker %s%s", markerPrefix, name)
+		}
+	}
+
+	return nil
+}
+
+// isNumber returns true if the value of the field is a number that can be compared with min and max.
+func isNumber(f field) bool {
+	if f.timeKind != timeKindNone || (f.named != nil && f.named.stringer) {
+		return false
+	}
+
+	return types.Typ[f.fieldType].Info()&types.IsNumeric != 0
+}
+
+// checkFieldValidation checks that the validation rules of the field can be applied to its type.
+func checkFieldValidation(f field) error {
+	v := f.validation
+
+	if v.maxItems != "" && !f.slice && !f.stringMap {
+		return fmt.Errorf("validation rule %s is only supported for slices and maps", validationMaxItems)
+	}
+
+	bounds := []struct {
+		name  string
+		value string
+	}{
+		{name: validationMin, value: v.min},
+		{name: validationMax, value: v.max},
+	}
+
+	for _, bound := range bounds {
+		name, value := bound.name, bound.value
+		if value == "" {
+			continue
+		}
+
+		if f.slice || f.stringMap || !isNumber(f) {
+			return fmt.Errorf("validation rule %s is only supported for numbers and pointers to numbers", name)
+		}
+
+		if err := checkBound(f, name, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// numberSizes are the sizes of the number types in the generated code, where int and uint are 64 bits.
+var numberSizes = types.SizesFor("gc", "amd64")
+
+// checkBound checks that the value of the min or max validation rule is a constant of the type of the number field,
+// so that the generated code that compares the field with it compiles: integer fields require integers, and
+// the value must be in the range of the type.
+func checkBound(f field, name, value string) error {
+	basic := types.Typ[f.fieldType]
+	bitSize := int(numberSizes.Sizeof(basic) * 8)
+	info := basic.Info()
+
+	var err error
+
+	switch {
+	case info&types.IsUnsigned != 0:
+		if strings.HasPrefix(value, "-") {
+			return fmt.Errorf("validation rule %s must not be negative for unsigned integer fields, got %s", name, value)
+		}
+
+		_, err = strconv.ParseUint(value, 10, bitSize)
+	case info&types.IsInteger != 0:
+		_, err = strconv.ParseInt(value, 10, bitSize)
+	default:
+		_, err = strconv.ParseFloat(value, bitSize)
+	}
+
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return fmt.Errorf("validation rule %s is out of the range of %s fields, got %s", name, basic.Name(), value)
+	case err != nil:
+		return fmt.Errorf("validation rule %s must be an integer for integer fields, got %s", name, value)
+	default:
+		return nil
+	}
+}
+
+// hasValidation returns true if any of the fields, including the fields of the embedded structs, has validation rules.
+func hasValidation(fields []field) bool {
+	for _, f := range fields {
+		if f.validation.isSet() || (f.embeddedStruct && hasValidation(f.embeddedStructFields)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// validationRule is a validation rule of a field in the Validate method.
+type validationRule struct {
+	// Condition is the condition under which the field violates the rule.
+	Condition string
+	// Field is the name of the field.
+	Field string
+	// Message describes the violated rule.
+	Message string
+	// EmbeddedField is the name of the embedded struct that is validated by its Validate method.
+	// If set, the other fields are unused.
+	EmbeddedField string
+}
+
+// getUnsetCondition returns the condition that is true when the field is not set.
+func getUnsetCondition(f field, value string) string {
+	switch {
+	case f.pointer:
+		return value + " == nil"
+	case f.slice, f.stringMap:
+		return fmt.Sprintf("len(%s) == 0", value)
+	case f.named != nil && f.named.stringer:
+		return value + `.String() == ""`
+	case f.timeKind == timeKindTimestamp:
+		return value + ".IsZero()"
+	case f.fieldType == types.String:
+		return value + ` == ""`
+	case f.fieldType == types.Bool:
+		return "!" + value
+	default:
+		return value + " == 0"
+	}
+}
+
+// createValidationRules creates the validationRules of the field.
+func createValidationRules(f field) []validationRule {
+	value := "d." + f.name
+
+	if f.embeddedStruct {
+		if !hasValidation(f.embeddedStructFields) {
+			return nil
+		}
+
+		return []validationRule{{EmbeddedField: f.name}}
+	}
+
+	var rules []validationRule
+
+	if f.validation.required {
+		rules = append(rules, validationRule{
+			Condition: getUnsetCondition(f, value),
+			Field:     f.name,
+			Message:   "is required",
+		})
+	}
+
+	// a nil pointer means the value is unknown, so min and max only apply to set values
+	comparedValue, condition := value, ""
+	if f.pointer {
+		comparedValue, condition = "*"+value, value+" != nil && "
+	}
+
+	if f.validation.min != "" {
+		rules = append(rules, validationRule{
+			Condition: fmt.Sprintf("%s%s < %s", condition, comparedValue, f.validation.min),
+			Field:     f.name,
+			Message:   "must be at least " + f.validation.min,
+		})
+	}
+
+	if f.validation.max != "" {
+		rules = append(rules, validationRule{
+			Condition: fmt.Sprintf("%s%s > %s", condition, comparedValue, f.validation.max),
+			Field:     f.name,
+			Message:   "must be at most " + f.validation.max,
+		})
+	}
+
+	if f.validation.maxItems != "" {
+		rules = append(rules, validationRule{
+			Condition: fmt.Sprintf("len(%s) > %s", value, f.validation.maxItems),
+			Field:     f.name,
+			Message:   fmt.Sprintf("must have at most %s items", f.validation.maxItems),
+		})
+	}
+
+	return rules
+}
@@ -0,0 +1,258 @@
+//go:build generator
+
+package main
+
+import (
+	"go/types"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestSplitFieldMarkers(t *testing.T) {
+	t.Parallel()
+	g := NewGomegaWithT(t)
+
+	doc, markers := splitFieldMarkers("Counter is a counter.\nMore comments.\n\n  +telemetry:min=0\n+telemetry:required\n")
+
+	g.Expect(doc).To(Equal("Counter is a counter.\nMore comments.\n\n"))
+	g.Expect(markers).To(Equal([]string{"min=0", "required"}))
+}
+
+func TestCreateValidationRules(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		expected []validationRule
+		field    field
+	}{
+		{
+			name:  "required string",
+			field: field{name: "Name", fieldType: types.String, validation: fieldValidation{required: true}},
+			expected: []validationRule{
+				{Condition: `d.Name == ""`, Field: "Name", Message: "is required"},
+			},
+		},
+		{
+			name: "required map",
+			field: field{
+				name:       "Labels",
+				fieldType:  types.String,
+				stringMap:  true,
+				validation: fieldValidation{required: true},
+			},
+			expected: []validationRule{
+				{Condition: "len(d.Labels) == 0", Field: "Labels", Message: "is required"},
+			},
+		},
+		{
+			name: "required timestamp",
+			field: field{
+				name:       "Started",
+				fieldType:  types.Int64,
+				timeKind:   timeKindTimestamp,
+				validation: fieldValidation{required: true},
+			},
+			expected: []validationRule{
+				{Condition: "d.Started.IsZero()", Field: "Started", Message: "is required"},
+			},
+		},
+		{
+			name: "bounded pointer",
+			field: field{
+				name:       "Count",
+				fieldType:  types.Int64,
+				pointer:    true,
+				validation: fieldValidation{required: true, min: "0", max: "10"},
+			},
+			expected: []validationRule{
+				{Condition: "d.Count == nil", Field: "Count", Message: "is required"},
+				{Condition: "d.Count != nil && *d.Count < 0", Field: "Count", Message: "must be at least 0"},
+				{Condition: "d.Count != nil && *d.Count > 10", Field: "Count", Message: "must be at most 10"},
+			},
+		},
+		{
+			name: "max items",
+			field: field{
+				name:       "Names",
+				fieldType:  types.String,
+				slice:      true,
+				validation: fieldValidation{maxItems: "100"},
+			},
+			expected: []validationRule{
+				{Condition: "len(d.Names) > 100", Field: "Names", Message: "must have at most 100 items"},
+			},
+		},
+		{
+			name: "embedded struct with rules",
+			field: field{
+				name:           "ClusterData",
+				embeddedStruct: true,
+				embeddedStructFields: []field{
+					{name: "Nodes", fieldType: types.Int64, validation: fieldValidation{min: "1"}},
+				},
+			},
+			expected: []validationRule{
+				{EmbeddedField: "ClusterData"},
+			},
+		},
+		{
+			name: "embedded struct without rules",
+			field: field{
+				name:           "ClusterData",
+				embeddedStruct: true,
+				embeddedStructFields: []field{
+					{name: "Nodes", fieldType: types.Int64},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			g.Expect(createValidationRules(test.field)).To(Equal(test.expected))
+		})
+	}
+}
+
+func TestApplyValidationRuleBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		value  string
+		expErr string
+	}{
+		{name: "integer", value: "-10"},
+		{name: "fraction", value: "0.5"},
+		{name: "exponent", value: "1e3"},
+		{name: "not a number", value: "zero", expErr: "validation rule min must be a finite number, got zero"},
+		{name: "infinity", value: "Inf", expErr: "validation rule min must be a finite number, got Inf"},
+		{name: "negative infinity", value: "-infinity", expErr: "validation rule min must be a finite number, got -infinity"},
+		{name: "NaN", value: "NaN", expErr: "validation rule min must be a finite number, got NaN"},
+		{name: "overflow", value: "1e400", expErr: "validation rule min must be a finite number, got 1e400"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			var v fieldValidation
+
+			found, err := v.applyValidationRule(validationMin, test.value, true)
+
+			g.Expect(found).To(BeTrue())
+
+			if test.expErr != "" {
+				g.Expect(err).To(MatchError(test.expErr))
+				g.Expect(v.min).To(BeEmpty())
+
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(v.min).To(Equal(test.value))
+		})
+	}
+}
+
+func TestCheckFieldValidationBounds(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		expErr     string
+		validation fieldValidation
+		fieldType  types.BasicKind
+	}{
+		{
+			name:       "int8 in range",
+			fieldType:  types.Int8,
+			validation: fieldValidation{min: "-128", max: "127"},
+		},
+		{
+			name:       "int8 out of range",
+			fieldType:  types.Int8,
+			validation: fieldValidation{min: "1000"},
+			expErr:     "validation rule min is out of the range of int8 fields, got 1000",
+		},
+		{
+			name:       "int16 below range",
+			fieldType:  types.Int16,
+			validation: fieldValidation{min: "-32769"},
+			expErr:     "validation rule min is out of the range of int16 fields, got -32769",
+		},
+		{
+			name:       "int out of range",
+			fieldType:  types.Int,
+			validation: fieldValidation{max: "9223372036854775808"},
+			expErr:     "validation rule max is out of the range of int fields, got 9223372036854775808",
+		},
+		{
+			name:       "uint8 out of range",
+			fieldType:  types.Uint8,
+			validation: fieldValidation{max: "256"},
+			expErr:     "validation rule max is out of the range of uint8 fields, got 256",
+		},
+		{
+			name:       "uint32 in range",
+			fieldType:  types.Uint32,
+			validation: fieldValidation{max: "4294967295"},
+		},
+		{
+			name:       "negative unsigned",
+			fieldType:  types.Uint16,
+			validation: fieldValidation{min: "-1"},
+			expErr:     "validation rule min must not be negative for unsigned integer fields, got -1",
+		},
+		{
+			name:       "fractional integer",
+			fieldType:  types.Int64,
+			validation: fieldValidation{max: "0.5"},
+			expErr:     "validation rule max must be an integer for integer fields, got 0.5",
+		},
+		{
+			name:       "exponent integer",
+			fieldType:  types.Int32,
+			validation: fieldValidation{max: "1e3"},
+			expErr:     "validation rule max must be an integer for integer fields, got 1e3",
+		},
+		{
+			name:       "float32 in range",
+			fieldType:  types.Float32,
+			validation: fieldValidation{min: "-0.5", max: "1e38"},
+		},
+		{
+			name:       "float32 out of range",
+			fieldType:  types.Float32,
+			validation: fieldValidation{max: "1e39"},
+			expErr:     "validation rule max is out of the range of float32 fields, got 1e39",
+		},
+		{
+			name:       "float64 in range",
+			fieldType:  types.Float64,
+			validation: fieldValidation{max: "1e300"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Parallel()
+			g := NewGomegaWithT(t)
+
+			err := checkFieldValidation(field{name: "Value", fieldType: test.fieldType, validation: test.validation})
+
+			if test.expErr != "" {
+				g.Expect(err).To(MatchError(test.expErr))
+				return
+			}
+
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package telemetry
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// MissingAttributeError is returned when the attribute of a field is missing, and the field doesn't allow it.
+type MissingAttributeError struct {
+	// Key is the key of the missing attribute.
+	Key attribute.Key
+}
+
+// Error returns the error message.
+func (e *MissingAttributeError) Error() string {
+	return fmt.Sprintf("attribute %s is missing", e.Key)
+}
+
+// AttributeTypeError is returned when the value of an attribute doesn't have the type of its field.
+type AttributeTypeError struct {
+	// Key is the key of the attribute.
+	Key attribute.Key
+	// Expected is the type of the field.
+	Expected attribute.Type
+	// Actual is the type of the value of the attribute.
+	Actual attribute.Type
+}
+
+// Error returns the error message.
+func (e *AttributeTypeError) Error() string {
+	return fmt.Sprintf("attribute %s has type %s, expected %s", e.Key, e.Actual, e.Expected)
+}
+
+// AttributeRangeError is returned when the value of an attribute is out of the range of the type of its field.
+type AttributeRangeError struct {
+	// Key is the key of the attribute.
+	Key attribute.Key
+	// Type is the type of the field.
+	Type string
+	// Value is the value of the attribute.
+	Value int64
+}
+
+// Error returns the error message.
+func (e *AttributeRangeError) Error() string {
+	return fmt.Sprintf("attribute %s has value %d, out of the range of %s", e.Key, e.Value, e.Type)
+}
+
+// convertibleInteger is an integer type whose values are exported as int64 attribute values,
+// but can't hold all of them.
+type convertibleInteger interface {
+	~int | ~int8 | ~int16 | ~int32 | ~uint8 | ~uint16 | ~uint32
+}
+
+// ConvertAttributeInt converts the int64 value of the attribute with the key to the integer type of its field.
+// If the value is out of the range of the type, it returns an *AttributeRangeError.
+// It is used by the generated FromAttributes functions.
+func ConvertAttributeInt[T convertibleInteger](key attribute.Key, value int64) (T, error) {
+	converted := T(value) //nolint:gosec // the conversion is checked below
+	if int64(converted) != value {
+		return 0, &AttributeRangeError{Key: key, Type: fmt.Sprintf("%T", converted), Value: value}
+	}
+
+	return converted, nil
+}
+
+// LookupAttribute returns the value of the attribute with the key and whether the attribute exists.
+// If the value doesn't have the type, it returns an *AttributeTypeError.
+// It is used by the generated FromAttributes functions.
+func LookupAttribute(
+	attrs []attribute.KeyValue,
+	key attribute.Key,
+	valueType attribute.Type,
+) (attribute.Value, bool, error) {
+	for _, attr := range attrs {
+		if attr.Key != key {
+			continue
+		}
+
+		if attr.Value.Type() != valueType {
+			return attribute.Value{}, false, &AttributeTypeError{
+				Key:      key,
+				Expected: valueType,
+				Actual:   attr.Value.Type(),
+			}
+		}
+
+		return attr.Value, true, nil
+	}
+
+	return attribute.Value{}, false, nil
+}
+
+// RequireAttribute returns the value of the attribute with the key.
+// If the attribute doesn't exist, it returns a *MissingAttributeError. If the value doesn't have the type,
+// it returns an *AttributeTypeError.
+// It is used by the generated FromAttributes functions.
+func RequireAttribute(
+	attrs []attribute.KeyValue,
+	key attribute.Key,
+	valueType attribute.Type,
+) (attribute.Value, error) {
+	value, exists, err := LookupAttribute(attrs, key, valueType)
+	if err != nil {
+		return attribute.Value{}, err
+	}
+
+	if !exists {
+		return attribute.Value{}, &MissingAttributeError{Key: key}
+	}
+
+	return value, nil
+}
+
+// LookupAttributeMap returns the values of the attributes of a map field, keyed by the map keys.
+// The key of such an attribute is the key of the field followed by a dot and the map key, so the generator rejects
+// the other keys with that prefix.
+// If a value doesn't have the type, it returns an *AttributeTypeError.
+// It is used by the generated FromAttributes functions.
+func LookupAttributeMap(
+	attrs []attribute.KeyValue,
+	key string,
+	valueType attribute.Type,
+) (map[string]attribute.Value, error) {
+	var values map[string]attribute.Value
+
+	prefix := key + "."
+
+	for _, attr := range attrs {
+		mapKey, found := strings.CutPrefix(string(attr.Key), prefix)
+		if !found {
+			continue
+		}
+
+		if attr.Value.Type() != valueType {
+			return nil, &AttributeTypeError{
+				Key:      attr.Key,
+				Expected: valueType,
+				Actual:   attr.Value.Type(),
+			}
+		}
+
+		if values == nil {
+			values = make(map[string]attribute.Value)
+		}
+
+		values[mapKey] = attr.Value
+	}
+
+	return values, nil
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestLookupAttribute(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("name", "value"),
+		attribute.Int64("count", 1),
+	}
+
+	value, exists, err := LookupAttribute(attrs, "count", attribute.INT64)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exists).To(BeTrue())
+	g.Expect(value.AsInt64()).To(Equal(int64(1)))
+
+	_, exists, err = LookupAttribute(attrs, "missing", attribute.INT64)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(exists).To(BeFalse())
+
+	_, _, err = LookupAttribute(attrs, "name", attribute.INT64)
+	g.Expect(err).To(Equal(&AttributeTypeError{Key: "name", Expected: attribute.INT64, Actual: attribute.STRING}))
+	g.Expect(err).To(MatchError("attribute name has type STRING, expected INT64"))
+}
+
+func TestRequireAttribute(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	attrs := []attribute.KeyValue{
+		attribute.Bool("enabled", true),
+	}
+
+	value, err := RequireAttribute(attrs, "enabled", attribute.BOOL)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(value.AsBool()).To(BeTrue())
+
+	_, err = RequireAttribute(attrs, "missing", attribute.BOOL)
+	g.Expect(err).To(Equal(&MissingAttributeError{Key: "missing"}))
+	g.Expect(err).To(MatchError("attribute missing is missing"))
+
+	_, err = RequireAttribute(attrs, "enabled", attribute.STRING)
+	g.Expect(err).To(BeAssignableToTypeOf(&AttributeTypeError{}))
+}
+
+func TestLookupAttributeMap(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("labels.a", "first"),
+		attribute.String("labels", "not a map value"),
+		attribute.String("labels.b", "second"),
+		attribute.Int64("labelsCount", 2),
+	}
+
+	values, err := LookupAttributeMap(attrs, "labels", attribute.STRING)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(Equal(map[string]attribute.Value{
+		"a": attribute.StringValue("first"),
+		"b": attribute.StringValue("second"),
+	}))
+
+	values, err = LookupAttributeMap(attrs, "missing", attribute.STRING)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(values).To(BeNil())
+
+	_, err = LookupAttributeMap(attrs, "labels", attribute.INT64)
+	g.Expect(err).To(Equal(&AttributeTypeError{Key: "labels.a", Expected: attribute.INT64, Actual: attribute.STRING}))
+}
+
+func TestConvertAttributeInt(t *testing.T) {
+	t.Parallel()
+	g := NewWithT(t)
+
+	value, err := ConvertAttributeInt[int8]("count", -128)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(value).To(Equal(int8(-128)))
+
+	_, err = ConvertAttributeInt[int8]("count", 300)
+	g.Expect(err).To(Equal(&AttributeRangeError{Key: "count", Type: "int8", Value: 300}))
+	g.Expect(err).To(MatchError("attribute count has value 300, out of the range of int8"))
+
+	_, err = ConvertAttributeInt[uint32]("count", -1)
+	g.Expect(err).To(Equal(&AttributeRangeError{Key: "count", Type: "uint32", Value: -1}))
+
+	_, err = ConvertAttributeInt[uint32]("count", 1<<32)
+	g.Expect(err).To(BeAssignableToTypeOf(&AttributeRangeError{}))
+
+	unsigned, err := ConvertAttributeInt[uint32]("count", 1<<32-1)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unsigned).To(Equal(uint32(1<<32 - 1)))
+}
@@ -22,4 +22,141 @@ func (d *Data) Attributes() []attribute.KeyValue {
 	return attrs
 }
 
+// DataFromAttributes creates Data from the attributes returned by its Attributes method.
+func DataFromAttributes(attrs []attribute.KeyValue) (Data, error) {
+	var d Data
+
+	{
+		v, err := RequireAttribute(attrs, "ProjectName", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ProjectName = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ProjectVersion", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ProjectVersion = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ProjectArchitecture", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ProjectArchitecture = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ClusterID", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ClusterID = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ClusterVersion", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ClusterVersion = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ClusterPlatform", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ClusterPlatform = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "InstallationID", attribute.STRING)
+		if err != nil {
+			return Data{}, err
+		}
+		d.InstallationID = v.AsString()
+	}
+
+	{
+		v, err := RequireAttribute(attrs, "ClusterNodeCount", attribute.INT64)
+		if err != nil {
+			return Data{}, err
+		}
+		d.ClusterNodeCount = v.AsInt64()
+	}
+
+	return d, nil
+}
+
+// TelemetryFields returns the metadata of the fields of Data,
+// including the fields of the embedded structs.
+// d may be nil, because the metadata doesn't depend on the values of the fields.
+func (d *Data) TelemetryFields() []FieldInfo {
+	return []FieldInfo{
+		{
+			Key:         "ProjectName",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ProjectName is the name of the project.",
+			Source:      "Data",
+		},
+		{
+			Key:         "ProjectVersion",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ProjectVersion is the version of the project.",
+			Source:      "Data",
+		},
+		{
+			Key:         "ProjectArchitecture",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ProjectArchitecture is the architecture of the project. For example, \"amd64\".",
+			Source:      "Data",
+		},
+		{
+			Key:         "ClusterID",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ClusterID is the unique id of the Kubernetes cluster where the project is installed.\nIt is the UID of the `kube-system` Namespace.",
+			Source:      "Data",
+		},
+		{
+			Key:         "ClusterVersion",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ClusterVersion is the Kubernetes version of the cluster.",
+			Source:      "Data",
+		},
+		{
+			Key:         "ClusterPlatform",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "ClusterPlatform is the Kubernetes platform of the cluster.",
+			Source:      "Data",
+		},
+		{
+			Key:         "InstallationID",
+			GoType:      "string",
+			AvroType:    "string",
+			Description: "InstallationID is the unique id of the project installation in the cluster.",
+			Source:      "Data",
+		},
+		{
+			Key:         "ClusterNodeCount",
+			GoType:      "int64",
+			AvroType:    "long",
+			Description: "ClusterNodeCount is the number of nodes in the cluster.",
+			Source:      "Data",
+		},
+	}
+}
+
+var _ Describable = (*Data)(nil)
+
 var _ Exportable = (*Data)(nil)
@@ -40,6 +40,13 @@ type Exportable interface {
 	Attributes() []attribute.KeyValue
 }
 
+// Validatable allows validating telemetry data before the Exporter exports it.
+// The Exporter validates the Exportable data that also implements Validatable.
+type Validatable interface {
+	// Validate returns an error if the telemetry data is invalid.
+	Validate() error
+}
+
 // ExporterConfig contains the configuration for the Exporter.
 type ExporterConfig struct {
 	// SpanProvider contains SpanProvider for exporting spans.
@@ -128,7 +135,14 @@ func NewExporter(cfg ExporterConfig, options ...Option) (*Exporter, error) {
 }
 
 // Export exports telemetry data.
+// If the data implements Validatable, it is validated first, and invalid data is not exported.
 func (e *Exporter) Export(ctx context.Context, exportable Exportable) error {
+	if validatable, ok := exportable.(Validatable); ok {
+		if err := validatable.Validate(); err != nil {
+			return fmt.Errorf("invalid telemetry data: %w", err)
+		}
+	}
+
 	spanExporter, err := e.spanProvider(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create span exporter: %w", err)
@@ -21,6 +21,15 @@ func (d exportableData) Attributes() []attribute.KeyValue {
 	return d.attributes
 }
 
+type validatableData struct {
+	validationErr error
+	exportableData
+}
+
+func (d validatableData) Validate() error {
+	return d.validationErr
+}
+
 var _ = Describe("Exporter", func() {
 	When("SpanProvider works correctly", func() {
 		var (
@@ -69,6 +78,29 @@ var _ = Describe("Exporter", func() {
 			})
 		})
 
+		When("data is valid", func() {
+			It("exports data successfully", func() {
+				validData := validatableData{exportableData: data}
+
+				Expect(exporter.Export(context.Background(), validData)).To(Succeed())
+
+				Expect(fakeSpanExporter.ExportSpansCallCount()).To(Equal(1))
+			})
+		})
+
+		When("data is invalid", func() {
+			It("fails to export data", func() {
+				validationErr := &telemetry.ValidationError{Field: "ClusterID", Message: "is required"}
+				invalidData := validatableData{exportableData: data, validationErr: validationErr}
+
+				err := exporter.Export(context.Background(), invalidData)
+
+				Expect(err).To(MatchError(validationErr))
+				Expect(err).To(MatchError("invalid telemetry data: field ClusterID is required"))
+				Expect(fakeSpanExporter.ExportSpansCallCount()).To(BeZero())
+			})
+		})
+
 		When("SpanExporter returns an error", func() {
 			It("fails to export data", func() {
 				testError := errors.New("test error")
@@ -0,0 +1,24 @@
+package telemetry
+
+// FieldInfo is the metadata of a field of telemetry data, which is exported as attributes.
+type FieldInfo struct {
+	// Key is the key of the attribute of the field. The keys of the attributes of a map field are the key followed by
+	// a dot and the map key.
+	Key string
+	// GoType is the Go type of the field, like *int64 or map[string]string.
+	GoType string
+	// AvroType is the type of the field in the Avro scheme, like long or array<string>. Fields are nullable in the
+	// scheme, which the type doesn't include.
+	AvroType string
+	// Description is the doc string of the field.
+	Description string
+	// Source is the struct that declares the field, which is an embedded struct for the fields of embedded structs.
+	Source string
+}
+
+// Describable allows tooling like dry-run printers or redaction policies to get the metadata of the fields of
+// telemetry data at runtime.
+type Describable interface {
+	// TelemetryFields returns the metadata of the fields, including the fields of the embedded structs.
+	TelemetryFields() []FieldInfo
+}
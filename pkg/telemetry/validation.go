@@ -0,0 +1,16 @@
+package telemetry
+
+import "fmt"
+
+// ValidationError is returned by the generated Validate methods when a field violates its validation rule.
+type ValidationError struct {
+	// Field is the name of the field.
+	Field string
+	// Message describes the violated rule, like "must be at least 0".
+	Message string
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("field %s %s", e.Field, e.Message)
+}